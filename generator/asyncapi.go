@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"sort"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+const asyncAPIVersion = "2.6.0"
+
+// asyncAPIDocument is an AsyncAPI 2 document.
+type asyncAPIDocument struct {
+	AsyncAPI   string                      `yaml:"asyncapi"`
+	Info       asyncAPIInfo                `yaml:"info"`
+	Channels   map[string]*asyncAPIChannel `yaml:"channels"`
+	Components asyncAPIComponents          `yaml:"components,omitempty"`
+}
+
+type asyncAPIInfo struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// asyncAPIChannel is a channel. Clients subscribe to the messages sent by the service, and
+// publish the messages received by the service.
+type asyncAPIChannel struct {
+	Description string             `yaml:"description,omitempty"`
+	Subscribe   *asyncAPIOperation `yaml:"subscribe,omitempty"`
+	Publish     *asyncAPIOperation `yaml:"publish,omitempty"`
+}
+
+type asyncAPIOperation struct {
+	OperationID string           `yaml:"operationId,omitempty"`
+	Summary     string           `yaml:"summary,omitempty"`
+	Message     *asyncAPIMessage `yaml:"message"`
+}
+
+// asyncAPIMessage is a message, a reference to a message, or a choice of messages.
+type asyncAPIMessage struct {
+	Ref         string             `yaml:"$ref,omitempty"`
+	OneOf       []*asyncAPIMessage `yaml:"oneOf,omitempty"`
+	Name        string             `yaml:"name,omitempty"`
+	Description string             `yaml:"description,omitempty"`
+	Payload     *yaml.Node         `yaml:"payload,omitempty"`
+}
+
+type asyncAPIComponents struct {
+	Messages map[string]*asyncAPIMessage `yaml:"messages,omitempty"`
+	Schemas  *yaml.Node                  `yaml:"schemas,omitempty"`
+}
+
+// node renders the document.
+func (d *asyncAPIDocument) node() (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(d); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// buildAsyncAPIDocument builds an AsyncAPI document for the streaming methods and the events
+// of the files of the output unit. Message payloads are schemas of the OpenAPI reflector.
+func (g *OpenAPIv3Generator) buildAsyncAPIDocument(info *v3.Info) *asyncAPIDocument {
+	d := &asyncAPIDocument{
+		AsyncAPI: asyncAPIVersion,
+		Info: asyncAPIInfo{
+			Title:       info.Title,
+			Version:     info.Version,
+			Description: info.Description,
+		},
+		Channels: map[string]*asyncAPIChannel{},
+		Components: asyncAPIComponents{
+			Messages: map[string]*asyncAPIMessage{},
+		},
+	}
+	for _, file := range g.plugin.Files {
+		if !file.Generate || !g.unit.includesFile(file) {
+			continue
+		}
+		for _, service := range g.unit.servicesOf(file) {
+			for _, method := range service.Methods {
+				g.addStreamingChannel(d, service, method)
+			}
+		}
+		g.addEventChannels(d, file.Messages)
+	}
+
+	schemas := &v3.Document{
+		Components: &v3.Components{
+			Schemas: &v3.SchemasOrReferences{AdditionalProperties: []*v3.NamedSchemaOrReference{}},
+		},
+	}
+	g.addRequiredSchemasToDocumentV3(schemas)
+	if len(schemas.Components.Schemas.AdditionalProperties) > 0 {
+		pairs := schemas.Components.Schemas.AdditionalProperties
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].Name < pairs[j].Name
+		})
+		d.Components.Schemas = schemas.Components.Schemas.ToRawInfo()
+		expandZeroBounds(d.Components.Schemas)
+	}
+	return d
+}
+
+// addStreamingChannel adds a channel for a streaming method, named after its gRPC path. Like
+// paths, channels are left out if the build tags of the method don't match, or if client
+// streaming methods are excluded. The operations have the operation ID of the method
+// (<Service>_<Method>), except the publish operation of bidi streaming methods, which gets a
+// _Send suffix as the subscribe operation has it.
+func (g *OpenAPIv3Generator) addStreamingChannel(d *asyncAPIDocument, service *protogen.Service, method *protogen.Method) {
+	if streamingDirection(method) == "" {
+		return
+	}
+	methodParams, _ := proto.GetExtension(method.Desc.Options(), open_api_extensions.E_MethodParams).(*open_api_extensions.Parameters)
+	if !g.methodIncluded(method, methodParams) {
+		return
+	}
+	if isClientStreaming(method) && *g.conf.ClientStreaming == ClientStreamingExclude {
+		return
+	}
+	operationID := service.GoName + "_" + method.GoName
+	summary := g.filterCommentStringForSummary(method.Comments.Leading, method.GoName)
+	channel := &asyncAPIChannel{
+		Description: g.filterCommentString(method.Comments.Leading, false),
+	}
+	if method.Desc.IsStreamingServer() {
+		channel.Subscribe = &asyncAPIOperation{
+			OperationID: operationID,
+			Summary:     summary,
+			Message:     g.asyncAPIMessageRef(d, method.Output),
+		}
+	}
+	if method.Desc.IsStreamingClient() {
+		if channel.Subscribe != nil {
+			operationID += "_Send"
+		}
+		channel.Publish = &asyncAPIOperation{
+			OperationID: operationID,
+			Summary:     summary,
+			Message:     g.asyncAPIMessageRef(d, method.Input),
+		}
+	}
+	d.Channels["/"+string(service.Desc.FullName())+"/"+string(method.Desc.Name())] = channel
+}
+
+// addEventChannels adds the visible messages annotated with openapi.event to their channels.
+func (g *OpenAPIv3Generator) addEventChannels(d *asyncAPIDocument, messages []*protogen.Message) {
+	for _, message := range messages {
+		g.addEventChannels(d, message.Messages)
+		event, ok := proto.GetExtension(message.Desc.Options(), open_api_extensions.E_Event).(*open_api_extensions.Event)
+		if !ok || event == nil || event.GetChannel() == "" || !g.reflect.messageVisible(message.Desc) {
+			continue
+		}
+		channel, ok := d.Channels[event.GetChannel()]
+		if !ok {
+			channel = &asyncAPIChannel{}
+			d.Channels[event.GetChannel()] = channel
+		}
+		if event.GetDescription() != "" {
+			channel.Description = event.GetDescription()
+		}
+		ref := g.asyncAPIMessageRef(d, message)
+		switch {
+		case channel.Subscribe == nil:
+			channel.Subscribe = &asyncAPIOperation{Message: ref}
+		case channel.Subscribe.Message.Ref != "":
+			channel.Subscribe.Message = &asyncAPIMessage{OneOf: []*asyncAPIMessage{channel.Subscribe.Message, ref}}
+		default:
+			channel.Subscribe.Message.OneOf = append(channel.Subscribe.Message.OneOf, ref)
+		}
+	}
+}
+
+// asyncAPIMessageRef adds a message to the components, and returns a reference to it.
+func (g *OpenAPIv3Generator) asyncAPIMessageRef(d *asyncAPIDocument, message *protogen.Message) *asyncAPIMessage {
+	name := g.reflect.formatMessageName(message.Desc)
+	if _, ok := d.Components.Messages[name]; !ok {
+		m := &asyncAPIMessage{
+			Name:        name,
+			Description: g.filterCommentString(message.Comments.Leading, true),
+		}
+		if payload := g.reflect.schemaOrReferenceForMessage(message.Desc); payload != nil {
+			m.Payload = payload.ToRawInfo()
+			expandZeroBounds(m.Payload)
+		}
+		d.Components.Messages[name] = m
+	}
+	return &asyncAPIMessage{Ref: "#/components/messages/" + name}
+}
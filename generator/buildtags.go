@@ -0,0 +1,272 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+const (
+	UntaggedInclude = "include"
+	UntaggedExclude = "exclude"
+
+	// DefaultUntagged leaves the untagged methods out of public documents.
+	DefaultUntagged = "public_docs"
+)
+
+// checkUntagged checks the untagged option: include, exclude, or build tags separated by semicolons.
+func checkUntagged(untagged string) error {
+	switch untagged {
+	case UntaggedInclude, UntaggedExclude:
+		return nil
+	}
+	for _, tag := range strings.Split(untagged, ";") {
+		if x, err := parseBuildTagExpr(tag); err != nil || x != tagExpr(strings.TrimSpace(tag)) {
+			return fmt.Errorf("invalid untagged %q: must be include, exclude or build tags separated by semicolons", untagged)
+		}
+	}
+	return nil
+}
+
+// excludesUntagged reports whether elements without build tags are left out with the untagged
+// option: always for exclude, never for include, or if the build tag expressions hold for one of
+// its build tags. Without expressions, nothing is left out.
+func (r *OpenAPIv3Reflector) excludesUntagged(untagged string) bool {
+	switch {
+	case r.buildTags == nil || untagged == UntaggedInclude:
+		return false
+	case untagged == UntaggedExclude:
+		return true
+	}
+	for _, tag := range strings.Split(untagged, ";") {
+		tag = strings.TrimSpace(tag)
+		if r.buildTags.eval(func(t string) bool { return t == tag }) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildTags is the list of build tag expressions of the build_tag option, which can be repeated.
+// An element matches the expressions if it matches one of them.
+type BuildTags []string
+
+func (t *BuildTags) String() string {
+	return strings.Join(*t, ",")
+}
+
+func (t *BuildTags) Set(expression string) error {
+	if expression = strings.TrimSpace(expression); expression != "" {
+		*t = appendUnique(*t, expression)
+	}
+	return nil
+}
+
+// buildTagExpr is a boolean expression of build tags, e.g. "public_docs && !beta".
+type buildTagExpr interface {
+	eval(hasTag func(string) bool) bool
+}
+
+type (
+	tagExpr string
+	notExpr struct{ x buildTagExpr }
+	andExpr struct{ x, y buildTagExpr }
+	orExpr  struct{ x, y buildTagExpr }
+)
+
+func (e tagExpr) eval(hasTag func(string) bool) bool { return hasTag(string(e)) }
+func (e notExpr) eval(hasTag func(string) bool) bool { return !e.x.eval(hasTag) }
+func (e andExpr) eval(hasTag func(string) bool) bool { return e.x.eval(hasTag) && e.y.eval(hasTag) }
+func (e orExpr) eval(hasTag func(string) bool) bool  { return e.x.eval(hasTag) || e.y.eval(hasTag) }
+
+// parseBuildTags parses build tag expressions, or returns nil if there are none.
+func parseBuildTags(expressions BuildTags) (buildTagExpr, error) {
+	var expr buildTagExpr
+	for _, expression := range expressions {
+		x, err := parseBuildTagExpr(expression)
+		if err != nil {
+			return nil, err
+		}
+		if expr == nil {
+			expr = x
+		} else {
+			expr = orExpr{expr, x}
+		}
+	}
+	return expr, nil
+}
+
+// parseBuildTagExpr parses an expression of build tags combined with !, && and || (in order of
+// precedence), and parentheses.
+func parseBuildTagExpr(expression string) (buildTagExpr, error) {
+	p := &buildTagParser{input: expression}
+	expr := p.or()
+	if p.err == nil && p.next() != "" {
+		p.fail("unexpected " + p.token)
+	}
+	if p.err != nil {
+		return nil, fmt.Errorf("invalid build tag expression %q: %s", expression, p.err.Error())
+	}
+	return expr, nil
+}
+
+type buildTagParser struct {
+	input string
+	token string // Token read ahead by next, if not consumed.
+	err   error
+}
+
+// next returns the next token without consuming it: an operator, a parenthesis, a tag, or "".
+func (p *buildTagParser) next() string {
+	if p.token != "" {
+		return p.token
+	}
+	p.input = strings.TrimLeftFunc(p.input, unicode.IsSpace)
+	if p.input == "" {
+		return ""
+	}
+	for _, operator := range []string{"&&", "||", "!", "(", ")"} {
+		if strings.HasPrefix(p.input, operator) {
+			p.token, p.input = operator, p.input[len(operator):]
+			return p.token
+		}
+	}
+	i := strings.IndexFunc(p.input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.'
+	})
+	if i < 0 {
+		i = len(p.input)
+	}
+	if i == 0 {
+		p.fail("unexpected " + p.input[:1])
+		return ""
+	}
+	p.token, p.input = p.input[:i], p.input[i:]
+	return p.token
+}
+
+func (p *buildTagParser) consume() string {
+	token := p.next()
+	p.token = ""
+	return token
+}
+
+func (p *buildTagParser) fail(message string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("%s", message)
+	}
+}
+
+func (p *buildTagParser) or() buildTagExpr {
+	x := p.and()
+	for p.err == nil && p.next() == "||" {
+		p.consume()
+		x = orExpr{x, p.and()}
+	}
+	return x
+}
+
+func (p *buildTagParser) and() buildTagExpr {
+	x := p.not()
+	for p.err == nil && p.next() == "&&" {
+		p.consume()
+		x = andExpr{x, p.not()}
+	}
+	return x
+}
+
+func (p *buildTagParser) not() buildTagExpr {
+	switch token := p.consume(); token {
+	case "!":
+		return notExpr{p.not()}
+	case "(":
+		x := p.or()
+		if p.consume() != ")" {
+			p.fail("missing )")
+		}
+		return x
+	case "", "&&", "||", ")":
+		p.fail("missing build tag")
+		return tagExpr("")
+	default:
+		return tagExpr(token)
+	}
+}
+
+// includesBuildTags reports whether an element with build tags from annotations matches the
+// build tag expressions. Elements without build tags always match. Negative tags ("!beta") leave
+// an element out where the tag holds: an element with only negative tags has every other tag,
+// and an element with positive tags only has its tags. Without expressions, elements with build
+// tags match if unfiltered is true.
+func (r *OpenAPIv3Reflector) includesBuildTags(tags []string, unfiltered bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	if r.buildTags == nil {
+		return unfiltered
+	}
+	positive, negative := []string{}, []string{}
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "!") {
+			negative = append(negative, strings.TrimSpace(tag[1:]))
+		} else {
+			positive = append(positive, strings.TrimSpace(tag))
+		}
+	}
+	return r.buildTags.eval(func(tag string) bool {
+		switch {
+		case contains(positive, tag):
+			return true
+		case contains(negative, tag):
+			return false
+		}
+		return len(positive) == 0
+	})
+}
+
+// variant is a document generated with its own build tag expression, in a directory named
+// after it.
+type variant struct {
+	name      string
+	buildTags BuildTags
+}
+
+// parseVariants parses the variants option, a list of variants separated by semicolons. A variant
+// is a name and a build tag expression, e.g. "public:public_docs && !beta".
+func parseVariants(option string) ([]variant, error) {
+	variants := []variant{}
+	for _, v := range strings.Split(option, ";") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		name, expression, ok := strings.Cut(v, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid variant %q: must be a name and a build tag expression, e.g. public:public_docs", v)
+		}
+		for _, other := range variants {
+			if other.name == name {
+				return nil, fmt.Errorf("invalid variant %q: duplicate name %q", v, name)
+			}
+		}
+		buildTags := BuildTags{}
+		buildTags.Set(expression)
+		variants = append(variants, variant{name: name, buildTags: buildTags})
+	}
+	return variants, nil
+}
+
+// configuration returns the configuration of the variant: its build tags, and the output files
+// in its directory.
+func (v variant) configuration(conf Configuration) Configuration {
+	buildTags := v.buildTags
+	outputFile := path.Join(v.name, *conf.OutputFile)
+	conf.BuildTags = &buildTags
+	conf.OutputFile = &outputFile
+	if *conf.BundleFile != "" {
+		bundleFile := path.Join(v.name, *conf.BundleFile)
+		conf.BundleFile = &bundleFile
+	}
+	return conf
+}
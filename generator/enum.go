@@ -23,6 +23,8 @@ func enumKindSchema(field protoreflect.FieldDescriptor) *v3.SchemaOrReference {
 	return s
 }
 
+// enumsToV3Any returns the names of the values of an enum field, or of the values with the given
+// enum numbers.
 func enumsToV3Any(field protoreflect.FieldDescriptor, enumValues ...int32) []*v3.Any {
 
 	stringList := enumToStringSlice(field, enumValues...)
@@ -41,7 +43,7 @@ func enumToStringSlice(field protoreflect.FieldDescriptor, enumValues ...int32)
 	list := []string{}
 	values := field.Enum().Values()
 	for i := 0; i < values.Len(); i++ {
-		if len(enumValues) == 0 || has(enumValues, int32(values.Get(i).Index())) {
+		if len(enumValues) == 0 || has(enumValues, int32(values.Get(i).Number())) {
 			v := values.Get(i)
 			// skip default unspecified values
 			if removeUnspecified && strings.HasSuffix(string(v.Name()), "_UNSPECIFIED") {
@@ -54,7 +56,7 @@ func enumToStringSlice(field protoreflect.FieldDescriptor, enumValues ...int32)
 	return list
 }
 
-// enumValues returns of list of enum ids for a given field
+// enumValues returns the list of enum numbers for a given field
 func enumValues(field protoreflect.FieldDescriptor, removeUnspecified bool) []int32 {
 	values := field.Enum().Values()
 	var list []int32
@@ -65,16 +67,17 @@ func enumValues(field protoreflect.FieldDescriptor, removeUnspecified bool) []in
 			continue
 		}
 
-		list = append(list, int32(values.Get(i).Index()))
+		list = append(list, int32(v.Number()))
 	}
 	return list
 }
 
-func remove(list []int32, idxs ...int32) []int32 {
+// remove returns the values of list that are not in values
+func remove(list []int32, values ...int32) []int32 {
 	var filtered []int32
-	for _, v := range idxs {
-		if !has(list, v) {
-			filtered = append(filtered, int32(v))
+	for _, v := range list {
+		if !has(values, v) {
+			filtered = append(filtered, v)
 		}
 	}
 	return filtered
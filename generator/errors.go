@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	wk "github.com/google/gnostic/cmd/protoc-gen-openapi/generator/wellknown"
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/genproto/googleapis/rpc/code"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+// httpStatusForCode maps google.rpc.Code to HTTP status codes, as documented in google/rpc/code.proto.
+var httpStatusForCode = map[code.Code]int{
+	code.Code_OK:                  200,
+	code.Code_CANCELLED:           499,
+	code.Code_UNKNOWN:             500,
+	code.Code_INVALID_ARGUMENT:    400,
+	code.Code_DEADLINE_EXCEEDED:   504,
+	code.Code_NOT_FOUND:           404,
+	code.Code_ALREADY_EXISTS:      409,
+	code.Code_PERMISSION_DENIED:   403,
+	code.Code_UNAUTHENTICATED:     401,
+	code.Code_RESOURCE_EXHAUSTED:  429,
+	code.Code_FAILED_PRECONDITION: 400,
+	code.Code_ABORTED:             409,
+	code.Code_OUT_OF_RANGE:        400,
+	code.Code_UNIMPLEMENTED:       501,
+	code.Code_INTERNAL:            500,
+	code.Code_UNAVAILABLE:         503,
+	code.Code_DATA_LOSS:           500,
+}
+
+// methodErrors merges the errors of a service and a method. Method errors replace service
+// errors with the same code.
+func methodErrors(serviceErrors, methodErrors *open_api_extensions.Errors) []*open_api_extensions.Error {
+	errors := []*open_api_extensions.Error{}
+	for _, e := range serviceErrors.GetErrors() {
+		overridden := false
+		for _, m := range methodErrors.GetErrors() {
+			if m.GetCode() == e.GetCode() {
+				overridden = true
+			}
+		}
+		if !overridden {
+			errors = append(errors, e)
+		}
+	}
+	return append(errors, methodErrors.GetErrors()...)
+}
+
+// addErrorResponsesV3 adds a response for the HTTP status of every error of a method, before
+// the default response. Errors with the same HTTP status share a response.
+func (g *OpenAPIv3Generator) addErrorResponsesV3(d *v3.Document, op *v3.Operation, errors []*open_api_extensions.Error) {
+	if len(errors) == 0 {
+		return
+	}
+	byStatus := map[int][]*open_api_extensions.Error{}
+	for _, e := range errors {
+		c, ok := code.Code_value[e.GetCode()]
+		if !ok || c == int32(code.Code_OK) {
+			log.Printf("unsupported error code %q of %s", e.GetCode(), op.OperationId)
+			continue
+		}
+		status := httpStatusForCode[code.Code(c)]
+		byStatus[status] = append(byStatus[status], e)
+	}
+	statuses := make([]int, 0, len(byStatus))
+	for status := range byStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	responses := []*v3.NamedResponseOrReference{}
+	for _, status := range statuses {
+		descriptions := []string{}
+		details := []string{}
+		for _, e := range byStatus[status] {
+			description := e.GetCode()
+			if e.GetDescription() != "" {
+				description += ": " + e.GetDescription()
+			}
+			descriptions = append(descriptions, description)
+			details = append(details, e.GetDetails()...)
+		}
+		responses = append(responses, &v3.NamedResponseOrReference{
+			Name: strconv.Itoa(status),
+			Value: &v3.ResponseOrReference{
+				Oneof: &v3.ResponseOrReference_Response{
+					Response: &v3.Response{
+						Description: strings.Join(descriptions, "\n"),
+						Content:     g.errorContentV3(d, details),
+					},
+				},
+			},
+		})
+	}
+
+	// Keep the default response last.
+	all := op.Responses.ResponseOrReference
+	i := len(all)
+	if i > 0 && all[i-1].Name == "default" {
+		i--
+	}
+	op.Responses.ResponseOrReference = append(append(append([]*v3.NamedResponseOrReference{}, all[:i]...), responses...), all[i:]...)
+}
+
+// errorContentV3 returns the content of error responses, with the given error details.
+func (g *OpenAPIv3Generator) errorContentV3(d *v3.Document, details []string) *v3.MediaTypes {
+	anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+	schemaName := g.addErrorSchemaV3(d)
+	schema := &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Reference{
+			Reference: &v3.Reference{XRef: "#/components/schemas/" + schemaName}}}
+
+	items := []*v3.SchemaOrReference{}
+	seen := []string{}
+	for _, name := range details {
+		if contains(seen, name) {
+			continue
+		}
+		seen = append(seen, name)
+		message := g.findMessage(strings.TrimPrefix(name, "."), "google.rpc."+name)
+		if message == nil {
+			log.Printf("unable to find error details %s", name)
+			continue
+		}
+		items = append(items, &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{
+					AllOf: []*v3.SchemaOrReference{
+						{Oneof: &v3.SchemaOrReference_Reference{Reference: &v3.Reference{XRef: "#/components/schemas/" + anySchemaName}}},
+						g.reflect.schemaOrReferenceForMessage(message.Desc),
+					},
+				},
+			},
+		})
+	}
+	if len(items) > 0 {
+		g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
+		schema = errorDetailsSchema(schema, items)
+	}
+	if g.problemErrorModel() {
+		return &v3.MediaTypes{
+			AdditionalProperties: []*v3.NamedMediaType{
+				{Name: problemMediaType, Value: &v3.MediaType{Schema: schema}},
+			},
+		}
+	}
+	return wk.NewApplicationJsonMediaType(schema)
+}
+
+// errorDetailsSchema adds a details member with the given items to an error schema.
+func errorDetailsSchema(schema *v3.SchemaOrReference, items []*v3.SchemaOrReference) *v3.SchemaOrReference {
+	itemSchema := items[0]
+	if len(items) > 1 {
+		itemSchema = &v3.SchemaOrReference{Oneof: &v3.SchemaOrReference_Schema{Schema: &v3.Schema{OneOf: items}}}
+	}
+	return &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Schema{
+			Schema: &v3.Schema{
+				AllOf: []*v3.SchemaOrReference{
+					schema,
+					{
+						Oneof: &v3.SchemaOrReference_Schema{
+							Schema: &v3.Schema{
+								Type: "object",
+								Properties: &v3.Properties{
+									AdditionalProperties: []*v3.NamedSchemaOrReference{
+										{
+											Name: "details",
+											Value: &v3.SchemaOrReference{
+												Oneof: &v3.SchemaOrReference_Schema{
+													Schema: &v3.Schema{
+														Type:  "array",
+														Items: &v3.ItemsItem{SchemaOrReference: []*v3.SchemaOrReference{itemSchema}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"log"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldBehaviorIdentifier is the IDENTIFIER field behavior, which was added to
+// google/api/field_behavior.proto after the version of genproto used here.
+const fieldBehaviorIdentifier = annotations.FieldBehavior(8)
+
+// fieldBehaviors returns the google.api.field_behavior annotations of a field.
+func fieldBehaviors(field protoreflect.FieldDescriptor) []annotations.FieldBehavior {
+	extension := proto.GetExtension(field.Options(), annotations.E_FieldBehavior)
+	if extension == nil {
+		return nil
+	}
+	behaviors, ok := extension.([]annotations.FieldBehavior)
+	if !ok {
+		log.Printf("unsupported extension type %T", extension)
+		return nil
+	}
+	return behaviors
+}
+
+// hasFieldBehavior reports whether a field is annotated with the field behavior.
+func hasFieldBehavior(field protoreflect.FieldDescriptor, behavior annotations.FieldBehavior) bool {
+	for _, b := range fieldBehaviors(field) {
+		if b == behavior {
+			return true
+		}
+	}
+	return false
+}
+
+// addFieldBehaviors maps the field behaviors onto the schema of a message field in a schema
+// variant. REQUIRED is handled by the caller, as it's part of the message schema, and OPTIONAL
+// is the default for all fields.
+func addFieldBehaviors(schema *v3.Schema, field protoreflect.FieldDescriptor, variant schemaVariant) {
+	for _, behavior := range fieldBehaviors(field) {
+		switch behavior {
+		case annotations.FieldBehavior_OUTPUT_ONLY:
+			schema.ReadOnly = true
+		case annotations.FieldBehavior_INPUT_ONLY:
+			schema.WriteOnly = true
+		case fieldBehaviorIdentifier:
+			// The identifier is ignored when creating a resource, and names the resource
+			// (so is required) when updating it.
+			schema.ReadOnly = variant != schemaVariantUpdate
+		}
+	}
+	addFieldBehaviorExtensions(schema, field)
+}
+
+// addFieldBehaviorExtensions maps the field behaviors that have no OpenAPI keyword onto
+// extensions of the schema. These also apply to parameters, where readOnly and writeOnly
+// have no meaning.
+func addFieldBehaviorExtensions(schema *v3.Schema, field protoreflect.FieldDescriptor) {
+	for _, behavior := range fieldBehaviors(field) {
+		switch behavior {
+		case annotations.FieldBehavior_IMMUTABLE:
+			addSchemaExtension(schema, "x-createOnly", true)
+		case annotations.FieldBehavior_UNORDERED_LIST:
+			addSchemaExtension(schema, "x-unordered-list", true)
+		case annotations.FieldBehavior_NON_EMPTY_DEFAULT:
+			addSchemaExtension(schema, "x-non-empty-default", true)
+		case fieldBehaviorIdentifier:
+			addSchemaExtension(schema, "x-identifier", true)
+		}
+	}
+}
+
+// addSchemaExtension sets a specification extension of the schema.
+func addSchemaExtension(schema *v3.Schema, name string, value interface{}) {
+	for _, ext := range schema.SpecificationExtension {
+		if ext.Name == name {
+			ext.Value = newV3Any(value)
+			return
+		}
+	}
+	schema.SpecificationExtension = append(schema.SpecificationExtension, &v3.NamedAny{
+		Name:  name,
+		Value: newV3Any(value),
+	})
+}
+
+func removeSchemaExtension(schema *v3.Schema, name string) {
+	for i, ext := range schema.SpecificationExtension {
+		if ext.Name == name {
+			schema.SpecificationExtension = append(schema.SpecificationExtension[:i], schema.SpecificationExtension[i+1:]...)
+			return
+		}
+	}
+}
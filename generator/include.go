@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// includeMessagePatterns parses the include_messages option, a list of patterns of message full
+// names separated by semicolons, e.g. "tests.events.v1.*Event". A "*" matches any characters.
+func includeMessagePatterns(option string) ([]string, error) {
+	patterns := []string{}
+	for _, pattern := range strings.Split(option, ";") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid include_messages pattern %q: %s", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// includeMessagesV3 requires the schemas of the visible messages of the files of the output unit
+// matching the include_messages option, whether an operation references them or not, and returns
+// their names.
+func (g *OpenAPIv3Generator) includeMessagesV3() []string {
+	patterns, _ := includeMessagePatterns(*g.conf.IncludeMessages) // checked by generate
+	if len(patterns) == 0 {
+		return nil
+	}
+	names := []string{}
+	var include func(messages []*protogen.Message)
+	include = func(messages []*protogen.Message) {
+		for _, message := range messages {
+			include(message.Messages)
+			if message.Desc.IsMapEntry() || !matchesAny(patterns, string(message.Desc.FullName())) || !g.reflect.messageVisible(message.Desc) {
+				continue
+			}
+			// Well-known types without a schema of their own are left out.
+			if ref, ok := g.reflect.schemaOrReferenceForMessage(message.Desc).GetOneof().(*v3.SchemaOrReference_Reference); ok {
+				names = appendUnique(names, strings.TrimPrefix(ref.Reference.XRef, schemaRefPrefix))
+			}
+		}
+	}
+	for _, file := range g.plugin.Files {
+		if file.Generate && g.unit.includesFile(file) {
+			include(file.Messages)
+		}
+	}
+	return names
+}
+
+// matchesAny reports whether a name matches one of the patterns, in which "*" matches any
+// characters.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// path.Match doesn't match "/" with "*", which full names don't have.
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"log"
+	"strings"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	operationMessageName           = "google.longrunning.Operation"
+	getOperationRequestMessageName = "google.longrunning.GetOperationRequest"
+	operationInfoExtensionName     = "google.longrunning.operation_info"
+)
+
+// longRunningOperation is an operation that returns a google.longrunning.Operation, typed
+// with the google.longrunning.operation_info of the method.
+type longRunningOperation struct {
+	op       *v3.Operation
+	response *v3.Response
+	lro      *longRunningExtension
+}
+
+// longRunningExtension is the x-lro extension of long-running operations.
+type longRunningExtension struct {
+	Response           string `yaml:"response,omitempty"`
+	Metadata           string `yaml:"metadata,omitempty"`
+	PollingOperationID string `yaml:"pollingOperationId,omitempty"`
+}
+
+// isGetOperationMethod reports whether a method polls long-running operations.
+func isGetOperationMethod(method *protogen.Method) bool {
+	return method.Input.Desc.FullName() == getOperationRequestMessageName &&
+		method.Output.Desc.FullName() == operationMessageName
+}
+
+// operationInfo holds the google.longrunning.operation_info of a method.
+type operationInfo struct {
+	responseType string
+	metadataType string
+}
+
+// readOperationInfo reads the google.longrunning.operation_info of a method, or returns nil. The
+// extension is read with the descriptor from the request, as the Go package of
+// google/longrunning/operations.proto depends on gRPC.
+func (g *OpenAPIv3Generator) readOperationInfo(method *protogen.Method) *operationInfo {
+	var extension protoreflect.ExtensionDescriptor
+	for _, file := range g.plugin.Files {
+		for _, ext := range file.Extensions {
+			if ext.Desc.FullName() == operationInfoExtensionName {
+				extension = ext.Desc
+			}
+		}
+	}
+	if extension == nil {
+		return nil
+	}
+	extensionType := dynamicpb.NewExtensionType(extension)
+	resolver := new(protoregistry.Types)
+	if err := resolver.RegisterExtension(extensionType); err != nil {
+		log.Printf("unable to register %s: %s", operationInfoExtensionName, err)
+		return nil
+	}
+
+	// Unmarshal the method options again to resolve the extension.
+	bytes, err := proto.Marshal(method.Desc.Options())
+	if err != nil {
+		return nil
+	}
+	options := &descriptorpb.MethodOptions{}
+	if err := (proto.UnmarshalOptions{Resolver: resolver}).Unmarshal(bytes, options); err != nil {
+		log.Printf("unable to read %s of %s: %s", operationInfoExtensionName, method.Desc.FullName(), err)
+		return nil
+	}
+	var info *operationInfo
+	options.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.FullName() != operationInfoExtensionName {
+			return true
+		}
+		fields := fd.Message().Fields()
+		info = &operationInfo{
+			responseType: v.Message().Get(fields.ByName("response_type")).String(),
+			metadataType: v.Message().Get(fields.ByName("metadata_type")).String(),
+		}
+		return false
+	})
+	return info
+}
+
+// findMessageByName finds a message by the name used in google.longrunning.operation_info,
+// which is either fully qualified or relative to the package of the method.
+func (g *OpenAPIv3Generator) findMessageByName(name string, method *protogen.Method) *protogen.Message {
+	name = strings.TrimPrefix(name, ".")
+	return g.findMessage(string(method.Desc.ParentFile().Package())+"."+name, name)
+}
+
+// findMessage returns the first message of the plugin files with one of the full names.
+func (g *OpenAPIv3Generator) findMessage(fullNames ...string) *protogen.Message {
+	for _, fullName := range fullNames {
+		for _, file := range g.plugin.Files {
+			if m := findMessageByFullName(fullName, file.Messages); m != nil {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func findMessageByFullName(fullName string, messages []*protogen.Message) *protogen.Message {
+	for _, message := range messages {
+		if string(message.Desc.FullName()) == fullName {
+			return message
+		}
+		if m := findMessageByFullName(fullName, message.Messages); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// typeLongRunningOperationV3 types the response and metadata of the operation returned by a
+// long-running method, as declared by its google.longrunning.operation_info.
+func (g *OpenAPIv3Generator) typeLongRunningOperationV3(op *v3.Operation, method *protogen.Method) {
+	if method.Output.Desc.FullName() != operationMessageName {
+		return
+	}
+	info := g.readOperationInfo(method)
+	if info == nil {
+		return
+	}
+	response, ok := op.Responses.ResponseOrReference[0].Value.Oneof.(*v3.ResponseOrReference_Response)
+	if !ok || response.Response.Content == nil || len(response.Response.Content.AdditionalProperties) == 0 {
+		return
+	}
+	mediaType := response.Response.Content.AdditionalProperties[0].Value
+
+	lro := &longRunningExtension{}
+	properties := &v3.Properties{}
+	for _, typed := range []struct {
+		field    string
+		typeName string
+		ref      *string
+	}{
+		{"response", info.responseType, &lro.Response},
+		{"metadata", info.metadataType, &lro.Metadata},
+	} {
+		if typed.typeName == "" {
+			continue
+		}
+		message := g.findMessageByName(typed.typeName, method)
+		if message == nil {
+			continue
+		}
+		schema := g.reflect.schemaOrReferenceForMessage(message.Desc)
+		if schema == nil {
+			// google.protobuf.Empty
+			continue
+		}
+		if ref, ok := schema.Oneof.(*v3.SchemaOrReference_Reference); ok {
+			*typed.ref = ref.Reference.XRef
+		}
+		anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+		properties.AdditionalProperties = append(properties.AdditionalProperties, &v3.NamedSchemaOrReference{
+			Name: typed.field,
+			Value: &v3.SchemaOrReference{
+				Oneof: &v3.SchemaOrReference_Schema{
+					Schema: &v3.Schema{
+						AllOf: []*v3.SchemaOrReference{
+							{Oneof: &v3.SchemaOrReference_Reference{Reference: &v3.Reference{XRef: "#/components/schemas/" + anySchemaName}}},
+							schema,
+						},
+					},
+				},
+			},
+		})
+	}
+	if len(properties.AdditionalProperties) > 0 {
+		mediaType.Schema = &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{
+					AllOf: []*v3.SchemaOrReference{
+						mediaType.Schema,
+						{Oneof: &v3.SchemaOrReference_Schema{Schema: &v3.Schema{Type: "object", Properties: properties}}},
+					},
+				},
+			},
+		}
+	}
+	g.longRunningOperations = append(g.longRunningOperations, longRunningOperation{op: op, response: response.Response, lro: lro})
+}
+
+// addLongRunningOperationsV3 adds the x-lro extension to long-running operations, and links
+// them to the operation polling them, if there is one.
+func (g *OpenAPIv3Generator) addLongRunningOperationsV3() {
+	for _, l := range g.longRunningOperations {
+		if g.getOperation.operationID != "" && g.getOperation.operationID != l.op.OperationId {
+			l.lro.PollingOperationID = g.getOperation.operationID
+			if l.response.Links == nil {
+				l.response.Links = &v3.LinksOrReferences{}
+			}
+			l.response.Links.AdditionalProperties = append(l.response.Links.AdditionalProperties, &v3.NamedLinkOrReference{
+				Name: "GetOperation",
+				Value: &v3.LinkOrReference{
+					Oneof: &v3.LinkOrReference_Link{
+						Link: g.getOperation.link("$response.body#/name", "Poll the long-running operation until it is done."),
+					},
+				},
+			})
+		}
+		l.op.SpecificationExtension = append(l.op.SpecificationExtension, &v3.NamedAny{
+			Name:  "x-lro",
+			Value: newV3Any(l.lro),
+		})
+	}
+}
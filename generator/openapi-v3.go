@@ -30,6 +30,7 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/anypb"
 	any_pb "google.golang.org/protobuf/types/known/anypb"
+	"gopkg.in/yaml.v3"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 	wk "github.com/google/gnostic/cmd/protoc-gen-openapi/generator/wellknown"
@@ -39,21 +40,37 @@ import (
 )
 
 type Configuration struct {
-	Version         *string
-	Title           *string
-	Description     *string
-	Naming          *string
-	FQSchemaNaming  *bool
-	EnumType        *string
-	CircularDepth   *int
-	DefaultResponse *bool
-	Validate        *bool
-	BuildTag        *string // Kolla
+	Version                *string
+	Title                  *string
+	Description            *string
+	Naming                 *string
+	FQSchemaNaming         *bool
+	EnumType               *string
+	CircularDepth          *int
+	DefaultResponse        *bool
+	Validate               *bool
+	BuildTags              *BuildTags // Kolla
+	Untagged               *string    // Kolla
+	ResourceIDPattern      *string    // Kolla
+	InputSchemas           *bool      // Kolla
+	PaginationDescriptions *bool      // Kolla
+	SuccessStatusCodes     *bool      // Kolla
+	ErrorModel             *string    // Kolla
+	StreamingContent       *string    // Kolla
+	ClientStreaming        *string    // Kolla
+	AsyncAPI               *bool      // Kolla
+	OpenAPIVersion         *string    // Kolla
+	OutputFormat           *string    // Kolla
+	OutputFile             *string    // Kolla
+	OutputMode             *string    // Kolla
+	SharedPackages         *string    // Kolla
+	BundleFile             *string    // Kolla
+	Variants               *string    // Kolla
+	IncludeMessages        *string    // Kolla
 }
 
 const (
-	infoURL            = "https://github.com/kollalabs/protoc-gen-openapi"
-	BuildTagPublicDocs = "public_docs"
+	infoURL = "https://github.com/kollalabs/protoc-gen-openapi"
 )
 
 // In order to dynamically add google.rpc.Status responses we need
@@ -72,6 +89,17 @@ type OpenAPIv3Generator struct {
 	linterRulePattern *regexp.Regexp
 	pathPattern       *regexp.Regexp
 	namedPathPattern  *regexp.Regexp
+
+	resourceGetOperations map[string]linkTarget            // Get methods, by starred resource pattern.
+	resourceLinkResponses []resourceLinkResponse           // Responses that may link to Get operations.
+	longRunningOperations []longRunningOperation           // Operations returning google.longrunning.Operation.
+	getOperation          linkTarget                       // Method polling long-running operations.
+	unit                  *outputUnit                      // Files and services of the document.
+	schemaPackages        map[string]string                // Proto packages of the generated schemas, by schema name.
+	includedSchemas       []string                         // Schemas of the include_messages option.
+	messageSchemas        map[string]protoreflect.FullName // Messages, by schema name.
+	serviceTags           []*v3.Tag                        // Tags of the services with annotated methods, documented or not.
+	err                   error                            // First error found while building the document.
 }
 
 // NewOpenAPIv3Generator creates a new generator for a protoc plugin invocation.
@@ -85,18 +113,131 @@ func NewOpenAPIv3Generator(plugin *protogen.Plugin, conf Configuration) *OpenAPI
 		linterRulePattern: regexp.MustCompile(`\(-- (?s:.)* --\)`), // Kolla
 		pathPattern:       regexp.MustCompile("{([^=}]+)}"),
 		namedPathPattern:  regexp.MustCompile("{(.+)=(.+)}"),
+
+		resourceGetOperations: make(map[string]linkTarget),
+		unit:                  &outputUnit{},
+		schemaPackages:        make(map[string]string),
 	}
 }
 
+// documentNode renders the document for the configured OpenAPI (or Swagger) version. Kolla
+func (g *OpenAPIv3Generator) documentNode(d *v3.Document) *yaml.Node {
+	rawInfo := d.ToRawInfo()
+	expandZeroBounds(rawInfo) // Kolla
+	switch *g.conf.OpenAPIVersion {
+	case OpenAPIVersion20:
+		rawInfo = convertToSwagger(rawInfo)
+	case OpenAPIVersion31:
+		convertToOpenAPI31(rawInfo)
+	}
+	return rawInfo
+}
+
 // Run runs the generator.
 func (g *OpenAPIv3Generator) Run() error {
-	d := g.buildDocumentV3()
-	bytes, err := d.YAMLValue("Generated with protoc-gen-openapi\n" + infoURL)
+	// Kolla: generate the documents of every variant.
+	variants, err := parseVariants(*g.conf.Variants)
 	if err != nil {
-		return fmt.Errorf("failed to marshal yaml: %s", err.Error())
+		return err
+	}
+	if len(variants) == 0 {
+		return g.generate()
+	}
+	for _, v := range variants {
+		if err := NewOpenAPIv3Generator(g.plugin, v.configuration(g.conf)).generate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate generates the documents of the configuration. Kolla
+func (g *OpenAPIv3Generator) generate() error {
+	outputFile, err := outputFilePath("output_file", *g.conf.OutputFile) // Kolla
+	if err != nil {
+		return err
+	}
+	if g.reflect.buildTags, err = parseBuildTags(*g.conf.BuildTags); err != nil { // Kolla
+		return err
+	}
+	if err := checkUntagged(*g.conf.Untagged); err != nil { // Kolla
+		return err
+	}
+	if err := checkErrorModel(*g.conf.ErrorModel); err != nil { // Kolla
+		return err
+	}
+	if err := checkStreamingOptions(*g.conf.StreamingContent, *g.conf.ClientStreaming); err != nil { // Kolla
+		return err
+	}
+	if err := checkOpenAPIVersion(*g.conf.OpenAPIVersion); err != nil { // Kolla
+		return err
+	}
+	if _, err := includeMessagePatterns(*g.conf.IncludeMessages); err != nil { // Kolla
+		return err
+	}
+	// Kolla: generate a document per unit of the output mode.
+	units, err := outputUnits(g.plugin, *g.conf.OutputMode)
+	if err != nil {
+		return err
+	}
+	bundleFile := ""
+	if *g.conf.BundleFile != "" {
+		if bundleFile, err = outputFilePath("bundle_file", *g.conf.BundleFile); err != nil {
+			return err
+		}
+	}
+	documents := []*outputDocument{}
+	for _, unit := range units {
+		generator := NewOpenAPIv3Generator(g.plugin, g.conf)
+		generator.unit = unit
+		generator.schemaPackages = g.schemaPackages
+		generator.reflect.buildTags = g.reflect.buildTags
+		d := generator.buildDocumentV3()
+		if generator.err != nil {
+			return generator.err
+		}
+		documents = append(documents, &outputDocument{file: unit.outputFile(outputFile), document: d, included: generator.includedSchemas})
+	}
+
+	// Kolla: move the schemas of shared packages to shared documents, and bundle the documents.
+	shared := []*outputDocument{}
+	if packages := sharedPackages(*g.conf.SharedPackages); len(packages) > 0 {
+		shared = g.shareSchemasV3(documents, outputFile, packages)
+	}
+	if bundleFile != "" {
+		documents = append(documents, &outputDocument{file: bundleFile, document: g.bundleDocumentsV3(documents, shared)})
+	}
+	if err := checkOutputFiles(append(documents, shared...)); err != nil {
+		return err
+	}
+	for _, o := range append(documents, shared...) {
+		if err := g.writeDocument(o.file, g.documentNode(o.document)); err != nil {
+			return err
+		}
+	}
+
+	// Kolla: document streaming methods and events with AsyncAPI, per unit of the output mode.
+	if *g.conf.AsyncAPI {
+		for i, unit := range units {
+			generator := NewOpenAPIv3Generator(g.plugin, g.conf)
+			generator.unit = unit
+			generator.reflect.buildTags = g.reflect.buildTags
+			asyncAPIDocument := generator.buildAsyncAPIDocument(documents[i].document.Info)
+			if generator.err != nil {
+				return generator.err
+			}
+			if unit.name != "" && len(asyncAPIDocument.Channels) == 0 {
+				continue
+			}
+			document, err := asyncAPIDocument.node()
+			if err != nil {
+				return fmt.Errorf("failed to marshal yaml: %s", err.Error())
+			}
+			if err := g.writeDocument(unit.asyncAPIFile(outputFile), document); err != nil {
+				return err
+			}
+		}
 	}
-	outputFile := g.plugin.NewGeneratedFile("openapi.yaml", "")
-	outputFile.Write(bytes)
 	return nil
 }
 
@@ -122,37 +263,38 @@ func (g *OpenAPIv3Generator) buildDocumentV3() *v3.Document {
 	// track of which schemas are referenced in the response so we can
 	// add them later.
 	for _, file := range g.plugin.Files {
-		if file.Generate {
+		if file.Generate && g.unit.includesFile(file) { // Kolla
 			// Merge any `Document` annotations with the current
 			extDocument := proto.GetExtension(file.Desc.Options(), v3.E_Document)
 			if extDocument != nil {
 				proto.Merge(d, extDocument.(*v3.Document))
 			}
 
-			g.addPathsToDocumentV3(d, file.Services)
+			g.addPathsToDocumentV3(d, g.unit.servicesOf(file))
 		}
 	}
 
-	// While we have required schemas left to generate, go through the files again
-	// looking for the related message and adding them to the document if required.
-	for len(g.reflect.requiredSchemas) > 0 {
-		count := len(g.reflect.requiredSchemas)
-		for _, file := range g.plugin.Files {
-			g.addSchemasForMessagesToDocumentV3(d, file.Messages)
-		}
-		g.reflect.requiredSchemas = g.reflect.requiredSchemas[count:len(g.reflect.requiredSchemas)]
-	}
+	// Kolla: link resource references in responses to the Get operations of the resources.
+	g.addResourceLinksV3()
+	g.addLongRunningOperationsV3()
+
+	// Kolla: add the schemas of the included messages, and remove the schemas that nothing references.
+	g.includedSchemas = g.includeMessagesV3()
+	g.addRequiredSchemasToDocumentV3(d)
+	pruneSchemasV3(d, g.includedSchemas...)
 
 	// If there is only 1 service, then use it's title for the
 	// document, if the document is missing it.
-	if len(d.Tags) == 1 {
-		if d.Info.Title == "" && d.Tags[0].Name != "" {
-			d.Info.Title = d.Tags[0].Name + " API"
+	// Kolla: the service counts even if build tags leave all its operations out.
+	if len(g.serviceTags) == 1 && (len(d.Tags) == 0 || len(d.Tags) == 1 && d.Tags[0] == g.serviceTags[0]) {
+		tag := g.serviceTags[0]
+		if d.Info.Title == "" && tag.Name != "" {
+			d.Info.Title = tag.Name + " API"
 		}
 		if d.Info.Description == "" {
-			d.Info.Description = d.Tags[0].Description
+			d.Info.Description = tag.Description
 		}
-		d.Tags[0].Description = ""
+		tag.Description = ""
 	}
 
 	allServers := []string{}
@@ -270,6 +412,49 @@ func (g *OpenAPIv3Generator) findField(name string, inMessage *protogen.Message)
 	return nil
 }
 
+// findFieldPath looks up a field by its path in a message, e.g. "book.name".
+func (g *OpenAPIv3Generator) findFieldPath(fieldPath string, inMessage *protogen.Message) *protogen.Field {
+	var field *protogen.Field
+	for _, name := range strings.Split(fieldPath, ".") {
+		if inMessage == nil {
+			return nil
+		}
+		if field = g.findField(name, inMessage); field == nil {
+			return nil
+		}
+		inMessage = field.Message
+	}
+	return field
+}
+
+// findHeaderField looks up the field a custom header refers to, either by name in the
+// request message or by the full name of a field in any message.
+func (g *OpenAPIv3Generator) findHeaderField(name string, inMessage *protogen.Message) *protogen.Field {
+	if field := g.findField(name, inMessage); field != nil {
+		return field
+	}
+	for _, file := range g.plugin.Files {
+		if field := findFieldByFullName(name, file.Messages); field != nil {
+			return field
+		}
+	}
+	return nil
+}
+
+func findFieldByFullName(fullName string, messages []*protogen.Message) *protogen.Field {
+	for _, message := range messages {
+		for _, field := range message.Fields {
+			if string(field.Desc.FullName()) == fullName {
+				return field
+			}
+		}
+		if field := findFieldByFullName(fullName, message.Messages); field != nil {
+			return field
+		}
+	}
+	return nil
+}
+
 func (g *OpenAPIv3Generator) findAndFormatFieldName(name string, inMessage *protogen.Message) string {
 	field := g.findField(name, inMessage)
 	if field != nil {
@@ -306,6 +491,17 @@ func (g *OpenAPIv3Generator) _buildQueryParamsV3(field *protogen.Field, depths m
 		return parameters
 	}
 
+	// Kolla: hidden fields aren't documented
+	if !g.reflect.fieldVisible(field.Desc) {
+		return parameters
+	}
+
+	// Kolla: output only fields are ignored in requests
+	if hasFieldBehavior(field.Desc, annotations.FieldBehavior_OUTPUT_ONLY) {
+		return parameters
+	}
+	required := hasFieldBehavior(field.Desc, annotations.FieldBehavior_REQUIRED)
+
 	if field.Desc.Kind() == protoreflect.MessageKind {
 		typeName := g.reflect.fullMessageTypeName(field.Desc.Message())
 
@@ -399,6 +595,8 @@ func (g *OpenAPIv3Generator) _buildQueryParamsV3(field *protogen.Field, depths m
 				for _, subParam := range subParams {
 					if param, ok := subParam.Oneof.(*v3.ParameterOrReference_Parameter); ok {
 						param.Parameter.Name = queryFieldName + "." + param.Parameter.Name
+						// Fields of an optional message are only required if the message is set.
+						param.Parameter.Required = param.Parameter.Required && required
 						parameters = append(parameters, subParam)
 					}
 				}
@@ -408,6 +606,9 @@ func (g *OpenAPIv3Generator) _buildQueryParamsV3(field *protogen.Field, depths m
 	} else if field.Desc.Kind() != protoreflect.GroupKind {
 		// schemaOrReferenceForField also handles array types
 		fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+		if schema, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Schema); ok { // Kolla
+			addFieldBehaviorExtensions(schema.Schema, field.Desc)
+		}
 		if *g.conf.Validate { // Kolla
 			g.addValidationRules(fieldSchema, field.Desc)
 		}
@@ -419,7 +620,7 @@ func (g *OpenAPIv3Generator) _buildQueryParamsV3(field *protogen.Field, depths m
 						Name:        queryFieldName,
 						In:          "query",
 						Description: fieldDescription,
-						Required:    false,
+						Required:    required,
 						Schema:      fieldSchema,
 					},
 				},
@@ -438,10 +639,12 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 	description string,
 	defaultHost string,
 	path string,
+	methodName string,
 	bodyField string,
 	inputMessage *protogen.Message,
 	outputMessage *protogen.Message,
 	customParams *open_api_extensions.Parameters, // Kolla
+	paginationOpts *open_api_extensions.Pagination, // Kolla
 ) (*v3.Operation, string) {
 	// coveredParameters tracks the parameters that have been used in the body or path.
 	coveredParameters := make([]string, 0)
@@ -451,6 +654,15 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 	// Initialize the list of operation parameters.
 	parameters := []*v3.ParameterOrReference{}
 
+	// Kolla: update methods (AIP-134) take a partial resource in the body.
+	updateMask := updateMaskField(methodName, inputMessage)
+	var updateMessage *protogen.Message
+	if updateMask != nil && bodyField != "*" {
+		if field := g.findField(bodyField, inputMessage); field != nil && field.Message != nil && !field.Desc.IsList() && !field.Desc.IsMap() {
+			updateMessage = field.Message
+		}
+	}
+
 	// Find simple path parameters like {id}
 	if allMatches := g.pathPattern.FindAllStringSubmatch(path, -1); allMatches != nil {
 		for _, matches := range allMatches {
@@ -467,6 +679,9 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 			if field != nil {
 				fieldSchema = g.reflect.schemaOrReferenceForField(field.Desc)
 				fieldDescription = g.filterCommentString(field.Comments.Leading, true)
+				if *g.conf.Validate { // Kolla
+					g.addValidationRules(fieldSchema, field.Desc)
+				}
 			} else {
 				// If field does not exist, it is safe to set it to string, as it is ignored downstream
 				fieldSchema = &v3.SchemaOrReference{
@@ -516,8 +731,22 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 		newPath := strings.Join(parts, "/")
 		path = strings.Replace(path, matches[0], newPath, 1)
 
+		// Kolla: constrain the variables with the resource name pattern of the bound field.
+		segmentPatterns := g.namedPathSegmentPatterns(g.findFieldPath(matches[1], inputMessage), starredPath)
+
 		// Add the named path parameters to the operation parameters.
-		for _, namedPathParameter := range namedPathParameters {
+		for i, namedPathParameter := range namedPathParameters {
+			fieldSchema := &v3.SchemaOrReference{
+				Oneof: &v3.SchemaOrReference_Schema{
+					Schema: &v3.Schema{
+						Type: "string",
+					},
+				},
+			}
+			if i < len(segmentPatterns) {
+				fieldSchema.GetSchema().Pattern = "^" + segmentPatterns[i] + "$"
+			}
+
 			parameters = append(parameters,
 				&v3.ParameterOrReference{
 					Oneof: &v3.ParameterOrReference_Parameter{
@@ -526,13 +755,7 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 							In:          "path",
 							Required:    true,
 							Description: "The " + namedPathParameter + " id.",
-							Schema: &v3.SchemaOrReference{
-								Oneof: &v3.SchemaOrReference_Schema{
-									Schema: &v3.Schema{
-										Type: "string",
-									},
-								},
-							},
+							Schema:      fieldSchema,
 						},
 					},
 				})
@@ -540,19 +763,9 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 	}
 
 	// If there are any customParams, then iterate over them and add them to the parameter list
-	// First check if there are is a build tag set and don't run this if it is not set
+	// First check if the build tags of the customParams match the build tag expression, as they are left out without one
 	if customParams != nil {
-		doGenerate := true
-		if customParams.BuildTags != nil && len(customParams.BuildTags) > 0 {
-			doGenerate = false
-			for _, tag := range customParams.BuildTags {
-				if tag == *g.conf.BuildTag {
-					doGenerate = true
-					break
-				}
-			}
-		}
-		if doGenerate {
+		if g.reflect.includesBuildTags(customParams.BuildTags, false) {
 			for _, header := range customParams.Headers {
 				name := ""
 				pattern := ""
@@ -576,6 +789,33 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 					required = *header.Required
 				}
 
+				headerSchema := &v3.SchemaOrReference{
+					Oneof: &v3.SchemaOrReference_Schema{
+						Schema: &v3.Schema{
+							Type: "string",
+						},
+					},
+				}
+				// The header can inherit the schema and validation rules of a message field
+				if header.Field != nil {
+					field := g.findHeaderField(*header.Field, inputMessage)
+					if field != nil && field.Desc.Kind() != protoreflect.MessageKind {
+						headerSchema = g.reflect.schemaOrReferenceForField(field.Desc)
+						if *g.conf.Validate {
+							g.addValidationRules(headerSchema, field.Desc)
+						}
+						// A field of the request sent in the header isn't a query parameter.
+						if field.Parent == inputMessage {
+							coveredParameters = append(coveredParameters, string(field.Desc.Name()))
+						}
+					} else {
+						log.Printf("header %s refers to unknown or non scalar field %s", name, *header.Field)
+					}
+				}
+				if schema, ok := headerSchema.Oneof.(*v3.SchemaOrReference_Schema); ok && pattern != "" {
+					schema.Schema.Pattern = pattern
+				}
+
 				parameter := &v3.ParameterOrReference{
 					Oneof: &v3.ParameterOrReference_Parameter{
 						Parameter: &v3.Parameter{
@@ -583,14 +823,7 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 							In:          "header",
 							Description: headerDescription,
 							Required:    required,
-							Schema: &v3.SchemaOrReference{
-								Oneof: &v3.SchemaOrReference_Schema{
-									Schema: &v3.Schema{
-										Type:    "string",
-										Pattern: pattern,
-									},
-								},
-							},
+							Schema:      headerSchema,
 						},
 					},
 				}
@@ -619,6 +852,15 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 			fieldName := string(field.Desc.Name())
 			if !contains(coveredParameters, fieldName) && fieldName != bodyField {
 				fieldParams := g.buildQueryParamsV3(field)
+				// Kolla: list the field paths that can be updated.
+				if field == updateMask && updateMessage != nil {
+					paths := g.updateMaskPaths(updateMessage, "", map[string]int{})
+					for _, fieldParam := range fieldParams {
+						if param, ok := fieldParam.Oneof.(*v3.ParameterOrReference_Parameter); ok {
+							param.Parameter.Description = updateMaskDescription(param.Parameter.Description, paths)
+						}
+					}
+				}
 				parameters = append(parameters, fieldParams...)
 			}
 		}
@@ -644,23 +886,13 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 
 	// Add the default reponse if needed
 	if *g.conf.DefaultResponse {
-		anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
-		anySchema := wk.NewGoogleProtobufAnySchema(anySchemaName)
-		g.addSchemaToDocumentV3(d, anySchema)
-
-		statusSchemaName := g.reflect.formatMessageName(statusProtoDesc)
-		statusSchema := wk.NewGoogleRpcStatusSchema(statusSchemaName, anySchemaName)
-		g.addSchemaToDocumentV3(d, statusSchema)
-
 		defaultResponse := &v3.NamedResponseOrReference{
 			Name: "default",
 			Value: &v3.ResponseOrReference{
 				Oneof: &v3.ResponseOrReference_Response{
 					Response: &v3.Response{
 						Description: "Default error response",
-						Content: wk.NewApplicationJsonMediaType(&v3.SchemaOrReference{
-							Oneof: &v3.SchemaOrReference_Reference{
-								Reference: &v3.Reference{XRef: "#/components/schemas/" + statusSchemaName}}}),
+						Content:     g.errorContentV3(d, nil), // Kolla
 					},
 				},
 			},
@@ -679,6 +911,11 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 		Responses:   responses,
 	}
 
+	// Kolla: describe the pagination of List methods (AIP-158).
+	if p := g.paginationForMethod(methodName, inputMessage, outputMessage, paginationOpts); p != nil {
+		g.addPaginationV3(op, p)
+	}
+
 	if defaultHost != "" {
 		hostURL, err := url.Parse(defaultHost)
 		if err == nil {
@@ -693,7 +930,7 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 
 		if bodyField == "*" {
 			// Pass the entire request message as the request body.
-			requestSchema = g.reflect.schemaOrReferenceForMessage(inputMessage.Desc)
+			requestSchema = g.reflect.schemaOrReferenceForInputMessage(inputMessage.Desc)
 
 		} else {
 			// If body refers to a message field, use that type.
@@ -710,7 +947,11 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 						}
 
 					case protoreflect.MessageKind:
-						requestSchema = g.reflect.schemaOrReferenceForMessage(field.Message.Desc)
+						if field.Message == updateMessage { // Kolla
+							requestSchema = g.reflect.schemaOrReferenceForUpdateMessage(field.Message.Desc)
+						} else {
+							requestSchema = g.reflect.schemaOrReferenceForInputMessage(field.Message.Desc)
+						}
 
 					default:
 						log.Printf("unsupported field type %+v", field.Desc)
@@ -774,11 +1015,13 @@ func (g *OpenAPIv3Generator) addOperationToDocumentV3(d *v3.Document, op *v3.Ope
 func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*protogen.Service) {
 	for _, service := range services {
 		annotationsCount := 0
+		operationsCount := 0 // Kolla: services without documented operations don't get a tag
 		serviceHeadersOpts := proto.GetExtension(service.Desc.Options(), open_api_extensions.E_ServiceParams)
 		var params *open_api_extensions.Parameters
 		if serviceHeadersOpts != nil && serviceHeadersOpts != open_api_extensions.E_ServiceParams.InterfaceOf(open_api_extensions.E_ServiceParams.Zero()) {
 			params = serviceHeadersOpts.(*open_api_extensions.Parameters)
 		}
+		serviceErrors, _ := proto.GetExtension(service.Desc.Options(), open_api_extensions.E_ServiceErrors).(*open_api_extensions.Errors) // Kolla
 		for _, method := range service.Methods {
 			comment := g.filterCommentString(method.Comments.Leading, false)
 			inputMessage := method.Input
@@ -796,6 +1039,8 @@ func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*pr
 				methodParams = methodOptionsParams.(*open_api_extensions.Parameters)
 			}
 
+			paginationOpts, _ := proto.GetExtension(method.Desc.Options(), open_api_extensions.E_Pagination).(*open_api_extensions.Pagination) // Kolla
+
 			extHTTP := proto.GetExtension(method.Desc.Options(), annotations.E_Http)
 			if extHTTP != nil && extHTTP != annotations.E_Http.InterfaceOf(annotations.E_Http.Zero()) {
 				annotationsCount++
@@ -824,20 +1069,13 @@ func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*pr
 					path = "unknown-unsupported"
 				}
 			}
-			// If build tags exist, and a built tag is set in the protoc command, then only generate the method if the build tag is set
-			doGenerate := true
-			// If a build tag is set in the protoc command, then only generate the method if the build tag is set on the proto options
-			if *g.conf.BuildTag != "" && *g.conf.BuildTag == BuildTagPublicDocs {
-				doGenerate = false
-			}
+			// If build tags exist, and a build tag expression is set in the protoc command, then only generate the method if its build tags match
+			// Methods without build tags have the build tags of their service or file
+			doGenerate := g.methodIncluded(method, methodParams)
 
-			if methodParams != nil && methodParams.BuildTags != nil && len(methodParams.BuildTags) > 0 {
-				for _, tag := range methodParams.BuildTags {
-					if tag == *g.conf.BuildTag {
-						doGenerate = true
-						break
-					}
-				}
+			// Kolla: client and bidi streaming methods can be left out.
+			if isClientStreaming(method) && *g.conf.ClientStreaming == ClientStreamingExclude {
+				doGenerate = false
 			}
 
 			if doGenerate {
@@ -846,7 +1084,13 @@ func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*pr
 					defaultHost := proto.GetExtension(service.Desc.Options(), annotations.E_DefaultHost).(string)
 
 					op, path2 := g.buildOperationV3(
-						d, summary, operationID, service.GoName, comment, defaultHost, path, body, inputMessage, outputMessage, params)
+						d, summary, operationID, service.GoName, comment, defaultHost, path, methodName, body, inputMessage, outputMessage, params, paginationOpts)
+
+					// Kolla: document streaming methods.
+					g.addStreamingV3(d, op, method)
+
+					// Kolla: set the status codes of the success responses.
+					g.setSuccessResponsesV3(op, method, methodName)
 
 					// Merge any `Operation` annotations with the current
 					extOperation := proto.GetExtension(method.Desc.Options(), v3.E_Operation)
@@ -855,14 +1099,52 @@ func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*pr
 					}
 
 					g.addOperationToDocumentV3(d, op, path2, methodName)
+					operationsCount++
+
+					// Kolla: remember Get methods of resources and responses that may link to them.
+					if matches := g.namedPathPattern.FindStringSubmatch(path); matches != nil && methodName == "GET" {
+						if _, ok := g.resourceGetOperations[matches[2]]; !ok {
+							g.resourceGetOperations[matches[2]] = linkTarget{operationID: op.OperationId}
+						}
+					}
+					if response, ok := op.Responses.ResponseOrReference[0].Value.Oneof.(*v3.ResponseOrReference_Response); ok {
+						g.resourceLinkResponses = append(g.resourceLinkResponses, resourceLinkResponse{response: response.Response, message: outputMessage})
+					}
+
+					// Kolla: document the errors of the method.
+					errors, _ := proto.GetExtension(method.Desc.Options(), open_api_extensions.E_MethodErrors).(*open_api_extensions.Errors)
+					g.addErrorResponsesV3(d, op, methodErrors(serviceErrors, errors))
+
+					// Kolla: type long-running operations, and remember the method polling them.
+					g.typeLongRunningOperationV3(op, method)
+					if methodName == "GET" && isGetOperationMethod(method) && g.getOperation.operationID == "" {
+						g.getOperation = linkTarget{operationID: op.OperationId, parameter: g.pathNameParameter(path, inputMessage)}
+					}
 				}
 			}
 		}
 
 		if annotationsCount > 0 {
 			comment := g.filterCommentString(service.Comments.Leading, false)
-			d.Tags = append(d.Tags, &v3.Tag{Name: service.GoName, Description: comment})
+			tag := &v3.Tag{Name: service.GoName, Description: comment}
+			g.serviceTags = append(g.serviceTags, tag)
+			if operationsCount > 0 {
+				d.Tags = append(d.Tags, tag)
+			}
+		}
+	}
+}
+
+// addRequiredSchemasToDocumentV3 adds the schemas of the referenced messages to the document.
+func (g *OpenAPIv3Generator) addRequiredSchemasToDocumentV3(d *v3.Document) {
+	// While we have required schemas left to generate, go through the files again
+	// looking for the related message and adding them to the document if required.
+	for len(g.reflect.requiredSchemas) > 0 {
+		count := len(g.reflect.requiredSchemas)
+		for _, file := range g.plugin.Files {
+			g.addSchemasForMessagesToDocumentV3(d, file.Messages)
 		}
+		g.reflect.requiredSchemas = g.reflect.requiredSchemas[count:len(g.reflect.requiredSchemas)]
 	}
 }
 
@@ -886,119 +1168,145 @@ func (g *OpenAPIv3Generator) addSchemasForMessagesToDocumentV3(d *v3.Document, m
 		schemaName := g.reflect.formatMessageName(message.Desc)
 
 		// Only generate this if we need it and haven't already generated it.
-		if !contains(g.reflect.requiredSchemas, schemaName) ||
-			contains(g.generatedSchemas, schemaName) {
-			continue
+		if contains(g.reflect.requiredSchemas, schemaName) &&
+			!contains(g.generatedSchemas, schemaName) {
+			g.addMessageSchemaToDocumentV3(d, message, schemaName, g.reflect.outputVariant(message.Desc))
 		}
 
-		// Kolla
-		xt := annotations.E_Resource
-		extension := proto.GetExtension(message.Desc.Options(), xt)
-		pattern := ""
-		if extension != nil && extension != xt.InterfaceOf(xt.Zero()) {
-			rule := extension.(*annotations.ResourceDescriptor)
-			if len(rule.Pattern) > 0 {
-				pattern = rule.Pattern[0]
-			}
+		// Kolla: the input variant of messages with read-only or write-only fields.
+		inputSchemaName := schemaName + inputSchemaSuffix
+		if contains(g.reflect.requiredSchemas, inputSchemaName) &&
+			!contains(g.generatedSchemas, inputSchemaName) && g.checkVariantSchemaName(message, inputSchemaName) {
+			g.addMessageSchemaToDocumentV3(d, message, inputSchemaName, schemaVariantInput)
 		}
-		// Kolla
 
-		typeName := g.reflect.fullMessageTypeName(message.Desc)
-		messageDescription := g.filterCommentString(message.Comments.Leading, true)
+		// Kolla: the partial variant of messages updated by update methods.
+		updateSchemaName := schemaName + updateSchemaSuffix
+		if contains(g.reflect.requiredSchemas, updateSchemaName) &&
+			!contains(g.generatedSchemas, updateSchemaName) && g.checkVariantSchemaName(message, updateSchemaName) {
+			g.addMessageSchemaToDocumentV3(d, message, updateSchemaName, schemaVariantUpdate)
+		}
+	}
+}
 
-		// `google.protobuf.Value` and `google.protobuf.Any` have special JSON transcoding
-		// so we can't just reflect on the message descriptor.
-		if typeName == ".google.protobuf.Value" {
-			g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufValueSchema(schemaName))
-			continue
-		} else if typeName == ".google.protobuf.Any" {
-			g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufAnySchema(schemaName))
-			continue
-		} else if typeName == ".google.rpc.Status" {
-			anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
-			g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
-			g.addSchemaToDocumentV3(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
+// addMessageSchemaToDocumentV3 adds the schema of a message, with the fields of the variant, to the document.
+func (g *OpenAPIv3Generator) addMessageSchemaToDocumentV3(d *v3.Document, message *protogen.Message, schemaName string, variant schemaVariant) {
+	// Kolla: references in the input variants point to the input variants of messages.
+	if variant == schemaVariantInput || variant == schemaVariantUpdate {
+		g.reflect.input = true
+		defer func() { g.reflect.input = false }()
+	}
+
+	// Kolla
+	resource := resourceDescriptor(message)
+	g.schemaPackages[schemaName] = string(message.Desc.ParentFile().Package())
+	// Kolla
+
+	typeName := g.reflect.fullMessageTypeName(message.Desc)
+	messageDescription := g.filterCommentString(message.Comments.Leading, true)
+
+	// `google.protobuf.Value` and `google.protobuf.Any` have special JSON transcoding
+	// so we can't just reflect on the message descriptor.
+	if typeName == ".google.protobuf.Value" {
+		g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufValueSchema(schemaName))
+		return
+	} else if typeName == ".google.protobuf.Any" {
+		g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufAnySchema(schemaName))
+		return
+	} else if typeName == ".google.rpc.Status" {
+		anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+		g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
+		g.addSchemaToDocumentV3(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
+		return
+	}
+
+	// Build an array holding the fields of the message.
+	definitionProperties := &v3.Properties{
+		AdditionalProperties: make([]*v3.NamedSchemaOrReference, 0),
+	}
+
+	var required []string
+	for _, field := range message.Fields {
+		// Kolla: skip the fields that aren't part of the variant, or aren't documented
+		if !variant.includes(field.Desc) || !g.reflect.fieldVisible(field.Desc) {
 			continue
 		}
 
-		// Build an array holding the fields of the message.
-		definitionProperties := &v3.Properties{
-			AdditionalProperties: make([]*v3.NamedSchemaOrReference, 0),
+		if hasFieldBehavior(field.Desc, annotations.FieldBehavior_REQUIRED) {
+			required = append(required, g.reflect.formatFieldName(field.Desc))
 		}
 
-		var required []string
-		for _, field := range message.Fields {
-			// Check the field annotations to see if this is a readonly or writeonly field.
-			inputOnly := false
-			outputOnly := false
-			extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
-			if extension != nil {
-				switch v := extension.(type) {
-				case []annotations.FieldBehavior:
-					for _, vv := range v {
-						switch vv {
-						case annotations.FieldBehavior_OUTPUT_ONLY:
-							outputOnly = true
-						case annotations.FieldBehavior_INPUT_ONLY:
-							inputOnly = true
-						case annotations.FieldBehavior_REQUIRED:
-							required = append(required, g.reflect.formatFieldName(field.Desc))
-						}
-					}
-				default:
-					log.Printf("unsupported extension type %T", extension)
-				}
-			}
+		// The field is either described by a reference or a schema.
+		fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+		if fieldSchema == nil {
+			continue
+		}
 
-			// The field is either described by a reference or a schema.
-			fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
-			if fieldSchema == nil {
-				continue
+		if schema, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Schema); ok {
+			if resource != nil && string(field.Desc.Name()) == resourceNameField(resource) { // Kolla
+				schema.Schema.Pattern = g.resourceNamePattern(message, resource)
 			}
-
-			if schema, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Schema); ok {
-				if field.Desc.Name() == "name" && pattern != "" { // Kolla
-					pathParamsRX := regexp.MustCompile(`{[a-z_A-Z0-9]*}`)
-					rPattern := "^" + pathParamsRX.ReplaceAllString(pattern, "[a-z2-7]{26}") + "$"
-					schema.Schema.Pattern = rPattern
-				}
-				// Get the field description from the comments.
-				schema.Schema.Description = g.filterCommentString(field.Comments.Leading, true)
-				if outputOnly {
-					schema.Schema.ReadOnly = true
-				}
-				if inputOnly {
-					schema.Schema.WriteOnly = true
-				}
+			if reference := resourceReference(field); reference != nil { // Kolla
+				addResourceReferenceExtension(schema.Schema, reference)
 			}
+			// Get the field description from the comments.
+			schema.Schema.Description = g.filterCommentString(field.Comments.Leading, true)
+			// Check the field annotations to see if this is a readonly or writeonly field.
+			addFieldBehaviors(schema.Schema, field.Desc, variant)
+		} else if reference, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Reference); ok && g.openAPI31() {
+			// Kolla: OpenAPI 3.1 references can have a description.
+			reference.Reference.Description = g.filterCommentString(field.Comments.Leading, true)
+		}
 
-			// Kolla
-			if *g.conf.Validate {
-				g.addValidationRules(fieldSchema, field.Desc)
+		// Kolla
+		if *g.conf.Validate {
+			g.addValidationRules(fieldSchema, field.Desc)
+			if validationRequired(field.Desc) {
+				required = appendUnique(required, g.reflect.formatFieldName(field.Desc))
 			}
-
-			definitionProperties.AdditionalProperties = append(
-				definitionProperties.AdditionalProperties,
-				&v3.NamedSchemaOrReference{
-					Name:  g.reflect.formatFieldName(field.Desc),
-					Value: fieldSchema,
-				},
-			)
 		}
 
-		// Add the schema to the components.schema list.
-		g.addSchemaToDocumentV3(d, &v3.NamedSchemaOrReference{
-			Name: schemaName,
-			Value: &v3.SchemaOrReference{
-				Oneof: &v3.SchemaOrReference_Schema{
-					Schema: &v3.Schema{
-						Type:        "object",
-						Description: messageDescription,
-						Properties:  definitionProperties,
-						Required:    required,
-					},
-				},
+		definitionProperties.AdditionalProperties = append(
+			definitionProperties.AdditionalProperties,
+			&v3.NamedSchemaOrReference{
+				Name:  g.reflect.formatFieldName(field.Desc),
+				Value: fieldSchema,
 			},
-		})
+		)
+	}
+
+	schema := &v3.Schema{
+		Type:        "object",
+		Description: messageDescription,
+		Properties:  definitionProperties,
+		Required:    required,
 	}
+
+	// Kolla
+	if resource != nil {
+		addResourceExtensions(schema, resource)
+	}
+	if *g.conf.Validate {
+		g.addMessageValidationRules(schema, message)
+	}
+	if variant == schemaVariantUpdate {
+		// Partial updates only contain the fields in the update mask, and the identifier.
+		schema.Required = nil
+		schema.OneOf = nil
+		for _, field := range message.Fields {
+			if hasFieldBehavior(field.Desc, fieldBehaviorIdentifier) && g.reflect.fieldVisible(field.Desc) {
+				schema.Required = append(schema.Required, g.reflect.formatFieldName(field.Desc))
+			}
+		}
+	}
+
+	// Add the schema to the components.schema list.
+	g.addSchemaToDocumentV3(d, &v3.NamedSchemaOrReference{
+		Name: schemaName,
+		Value: &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: schema,
+			},
+		},
+	})
 }
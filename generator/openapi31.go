@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OpenAPIVersion20 = "2.0"
+	OpenAPIVersion30 = "3.0"
+	OpenAPIVersion31 = "3.1"
+
+	jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// checkOpenAPIVersion checks the openapi_version option.
+func checkOpenAPIVersion(version string) error {
+	switch version {
+	case OpenAPIVersion20, OpenAPIVersion30, OpenAPIVersion31:
+		return nil
+	}
+	return fmt.Errorf("invalid openapi_version %q: must be 2.0, 3.0 or 3.1", version)
+}
+
+// openAPI31 reports whether the document is generated for OpenAPI 3.1.
+func (g *OpenAPIv3Generator) openAPI31() bool {
+	return g.conf.OpenAPIVersion != nil && *g.conf.OpenAPIVersion == OpenAPIVersion31
+}
+
+// convertToOpenAPI31 converts a rendered OpenAPI 3.0 document to OpenAPI 3.1, whose schemas
+// are JSON Schema 2020-12. Component schemas declare the dialect with $schema.
+func convertToOpenAPI31(document *yaml.Node) {
+	if i := mappingIndex(document, "openapi"); i >= 0 {
+		document.Content[i+1] = yamlString("3.1.0")
+		document.Content = insertMappingPair(document.Content, i+2, "jsonSchemaDialect", yamlString(jsonSchemaDialect))
+	}
+	var componentSchemas *yaml.Node
+	if components := mappingValue(document, "components"); components != nil {
+		componentSchemas = mappingValue(components, "schemas")
+	}
+	if componentSchemas != nil {
+		for i := 1; i < len(componentSchemas.Content); i += 2 {
+			schema := componentSchemas.Content[i]
+			convertSchemaToOpenAPI31(schema)
+			if schema.Kind == yaml.MappingNode && mappingIndex(schema, "$schema") < 0 {
+				schema.Content = insertMappingPair(schema.Content, 0, "$schema", yamlString(jsonSchemaDialect))
+			}
+		}
+	}
+	convertSchemasToOpenAPI31(document, componentSchemas)
+}
+
+// convertSchemasToOpenAPI31 converts the schemas of parameters, request bodies and responses.
+func convertSchemasToOpenAPI31(node *yaml.Node, skip *yaml.Node) {
+	if node == skip {
+		return
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "schema" {
+				convertSchemaToOpenAPI31(node.Content[i+1])
+			} else {
+				convertSchemasToOpenAPI31(node.Content[i+1], skip)
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			convertSchemasToOpenAPI31(item, skip)
+		}
+	}
+}
+
+// convertSchemaToOpenAPI31 replaces the OpenAPI 3.0 idioms of a schema and its subschemas.
+func convertSchemaToOpenAPI31(schema *yaml.Node) {
+	if schema.Kind != yaml.MappingNode {
+		return
+	}
+
+	// nullable: true becomes a "null" type.
+	if i := mappingIndex(schema, "nullable"); i >= 0 {
+		nullable := schema.Content[i+1].Value == "true"
+		schema.Content = append(schema.Content[:i], schema.Content[i+2:]...)
+		if t := mappingIndex(schema, "type"); nullable && t >= 0 && schema.Content[t+1].Kind == yaml.ScalarNode {
+			schema.Content[t+1] = &yaml.Node{
+				Kind:    yaml.SequenceNode,
+				Style:   yaml.FlowStyle,
+				Content: []*yaml.Node{schema.Content[t+1], yamlString("null")},
+			}
+		}
+	}
+
+	// Bytes are base64 encoded strings.
+	if i := mappingIndex(schema, "format"); i >= 0 && schema.Content[i+1].Value == "byte" {
+		schema.Content[i] = yamlString("contentEncoding")
+		schema.Content[i+1] = yamlString("base64")
+	}
+
+	// example becomes examples.
+	if i := mappingIndex(schema, "example"); i >= 0 {
+		schema.Content[i] = yamlString("examples")
+		schema.Content[i+1] = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{schema.Content[i+1]}}
+	}
+
+	// A single value enum is a constant.
+	if i := mappingIndex(schema, "enum"); i >= 0 && len(schema.Content[i+1].Content) == 1 {
+		schema.Content[i] = yamlString("const")
+		schema.Content[i+1] = schema.Content[i+1].Content[0]
+	}
+
+	// exclusiveMinimum and exclusiveMaximum are numbers instead of flags.
+	for _, bound := range []struct{ exclusive, inclusive string }{
+		{"exclusiveMinimum", "minimum"},
+		{"exclusiveMaximum", "maximum"},
+	} {
+		i := mappingIndex(schema, bound.exclusive)
+		if i < 0 {
+			continue
+		}
+		exclusive := schema.Content[i+1].Value == "true"
+		schema.Content = append(schema.Content[:i], schema.Content[i+2:]...)
+		if j := mappingIndex(schema, bound.inclusive); exclusive && j >= 0 {
+			schema.Content[j] = yamlString(bound.exclusive)
+		}
+	}
+
+	// A reference can have siblings, instead of being wrapped in allOf.
+	if i := mappingIndex(schema, "allOf"); i >= 0 && len(schema.Content[i+1].Content) == 1 {
+		if ref := schema.Content[i+1].Content[0]; ref.Kind == yaml.MappingNode && len(ref.Content) == 2 && ref.Content[0].Value == "$ref" {
+			schema.Content[i] = ref.Content[0]
+			schema.Content[i+1] = ref.Content[1]
+		}
+	}
+
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		value := schema.Content[i+1]
+		switch schema.Content[i].Value {
+		case "properties":
+			for j := 1; j < len(value.Content); j += 2 {
+				convertSchemaToOpenAPI31(value.Content[j])
+			}
+		case "items", "additionalProperties", "not":
+			convertSchemaToOpenAPI31(value)
+		case "allOf", "oneOf", "anyOf":
+			for _, item := range value.Content {
+				convertSchemaToOpenAPI31(item)
+			}
+		}
+	}
+}
+
+// mappingIndex returns the index of the key in a mapping node, or -1.
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value of the key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(node, key); i >= 0 {
+		return node.Content[i+1]
+	}
+	return nil
+}
+
+// insertMappingPair inserts a key and a value at the index of the content of a mapping node.
+func insertMappingPair(content []*yaml.Node, i int, key string, value *yaml.Node) []*yaml.Node {
+	pair := []*yaml.Node{yamlString(key), value}
+	return append(content[:i], append(pair, content[i:]...)...)
+}
+
+func yamlString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputFormatYAML = "yaml"
+	OutputFormatJSON = "json"
+	OutputFormatBoth = "both"
+
+	OutputModeSingle     = "single"
+	OutputModePerService = "per_service"
+	OutputModePerFile    = "per_file"
+	OutputModePerPackage = "per_package"
+
+	DefaultOutputFile = "openapi.yaml"
+
+	outputComment = "Generated with protoc-gen-openapi\n" + infoURL
+)
+
+// outputFilePath validates an option naming an output file, which is relative to the output
+// directory.
+func outputFilePath(option string, file string) (string, error) {
+	cleaned := path.Clean(file)
+	if file == "" || path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid %s %q: must be a relative path in the output directory", option, file)
+	}
+	return cleaned, nil
+}
+
+// outputUnit is the part of the request that a document is generated for: some files, or a
+// service. The zero value is the whole request.
+type outputUnit struct {
+	name    string           // Name of the output file, without extension.
+	files   []*protogen.File // Files of the unit, or nil for all files.
+	service *protogen.Service
+}
+
+// outputUnits returns the units of an output mode. Units without services are left out.
+func outputUnits(plugin *protogen.Plugin, mode string) ([]*outputUnit, error) {
+	switch mode {
+	case OutputModeSingle:
+		return []*outputUnit{{}}, nil
+	case OutputModePerService, OutputModePerFile, OutputModePerPackage:
+	default:
+		return nil, fmt.Errorf("invalid output_mode %q: must be single, per_service, per_file or per_package", mode)
+	}
+	units := []*outputUnit{}
+	packages := map[string]*outputUnit{}
+	for _, file := range plugin.Files {
+		if !file.Generate || len(file.Services) == 0 {
+			continue
+		}
+		switch mode {
+		case OutputModePerService:
+			for _, service := range file.Services {
+				units = append(units, &outputUnit{name: string(service.Desc.FullName()), files: []*protogen.File{file}, service: service})
+			}
+		case OutputModePerFile:
+			units = append(units, &outputUnit{name: strings.TrimSuffix(file.Desc.Path(), ".proto"), files: []*protogen.File{file}})
+		case OutputModePerPackage:
+			unit, ok := packages[string(file.Desc.Package())]
+			if !ok {
+				unit = &outputUnit{name: string(file.Desc.Package())}
+				packages[unit.name] = unit
+				units = append(units, unit)
+			}
+			unit.files = append(unit.files, file)
+		}
+	}
+	return units, nil
+}
+
+// includesFile reports whether the paths of a file are documented by the unit.
+func (u *outputUnit) includesFile(file *protogen.File) bool {
+	if u.files == nil {
+		return true
+	}
+	for _, f := range u.files {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
+// servicesOf returns the services of a file documented by the unit.
+func (u *outputUnit) servicesOf(file *protogen.File) []*protogen.Service {
+	if u.service == nil {
+		return file.Services
+	}
+	return []*protogen.Service{u.service}
+}
+
+// outputFile returns the output file of the unit: the output_file option for the whole request,
+// or the name of the unit in the directory of the option.
+func (u *outputUnit) outputFile(outputFile string) string {
+	if u.name == "" {
+		return outputFile
+	}
+	return path.Join(path.Dir(outputFile), u.name+path.Ext(outputFile))
+}
+
+// asyncAPIFile returns the AsyncAPI file of the unit: asyncapi.yaml for the whole request, or
+// the name of the unit with an .asyncapi.yaml extension, in the directory of the output_file option.
+func (u *outputUnit) asyncAPIFile(outputFile string) string {
+	name := "asyncapi"
+	if u.name != "" {
+		name = u.name + ".asyncapi"
+	}
+	return path.Join(path.Dir(outputFile), name+".yaml")
+}
+
+// checkOutputFiles checks that no two documents are written to the same file, e.g. the document
+// of a package with output_mode=per_package and the document of its shared schemas.
+func checkOutputFiles(documents []*outputDocument) error {
+	files := map[string]bool{}
+	for _, o := range documents {
+		if files[o.file] {
+			return fmt.Errorf("several documents are written to %s: the documents of output_mode, shared_packages and bundle_file must have different files", o.file)
+		}
+		files[o.file] = true
+	}
+	return nil
+}
+
+// writeDocument writes a document in the configured output formats. YAML documents are written
+// to the file, JSON documents to the file with a .json extension.
+func (g *OpenAPIv3Generator) writeDocument(file string, document *yaml.Node) error {
+	format := *g.conf.OutputFormat
+	if format != OutputFormatYAML && format != OutputFormatJSON && format != OutputFormatBoth {
+		return fmt.Errorf("invalid output_format %q: must be yaml, json or both", format)
+	}
+	base := strings.TrimSuffix(file, path.Ext(file))
+	if format == OutputFormatYAML || format == OutputFormatBoth {
+		yamlFile := file
+		if path.Ext(file) == ".json" {
+			yamlFile = base + ".yaml"
+		}
+		setReferenceExtensions(document, path.Ext(yamlFile))
+		bytes, err := yaml.Marshal(&yaml.Node{
+			Kind:        yaml.DocumentNode,
+			Content:     []*yaml.Node{document},
+			HeadComment: outputComment,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal yaml: %s", err.Error())
+		}
+		g.plugin.NewGeneratedFile(yamlFile, "").Write(bytes)
+	}
+	if format == OutputFormatJSON || format == OutputFormatBoth {
+		setReferenceExtensions(document, ".json")
+		bytes, err := jsonValue(document)
+		if err != nil {
+			return fmt.Errorf("failed to marshal json: %s", err.Error())
+		}
+		g.plugin.NewGeneratedFile(base+".json", "").Write(bytes)
+	}
+	return nil
+}
+
+// setReferenceExtensions sets the extension of the files in references to other documents, to
+// reference the documents written in the same format.
+func setReferenceExtensions(node *yaml.Node, ext string) {
+	for i, child := range node.Content {
+		if node.Kind == yaml.MappingNode && i%2 == 1 && node.Content[i-1].Value == "$ref" {
+			if j := strings.Index(child.Value, "#"); j > 0 {
+				file := child.Value[:j]
+				child.Value = strings.TrimSuffix(file, path.Ext(file)) + ext + child.Value[j:]
+			}
+			continue
+		}
+		setReferenceExtensions(child, ext)
+	}
+}
+
+// jsonValue renders a document as indented JSON, with the keys in the same order as the YAML.
+func jsonValue(node *yaml.Node) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := writeJSON(&compact, node); err != nil {
+		return nil, err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteString("\n")
+	return indented.Bytes(), nil
+}
+
+func writeJSON(buffer *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeJSON(buffer, node.Content[0])
+	case yaml.MappingNode:
+		buffer.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			writeJSONString(buffer, node.Content[i].Value)
+			buffer.WriteString(":")
+			if err := writeJSON(buffer, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("}")
+	case yaml.SequenceNode:
+		buffer.WriteString("[")
+		for i, item := range node.Content {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			if err := writeJSON(buffer, item); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("]")
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int", "!!float", "!!bool":
+			var value interface{}
+			if err := node.Decode(&value); err != nil {
+				return err
+			}
+			bytes, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			buffer.Write(bytes)
+		case "!!null":
+			buffer.WriteString("null")
+		default:
+			writeJSONString(buffer, node.Value)
+		}
+	case yaml.AliasNode:
+		return writeJSON(buffer, node.Alias)
+	}
+	return nil
+}
+
+// writeJSONString writes a JSON string, without escaping HTML characters.
+func writeJSONString(buffer *bytes.Buffer, value string) {
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	// Encode terminates the value with a newline.
+	buffer.Truncate(buffer.Len() - 1)
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"log"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+const (
+	pageSizeDescription  = "The maximum number of items to return. The service may return fewer than this value, and picks a default if unspecified."
+	pageTokenDescription = "A page token, received from a previous call. Provide this to retrieve the subsequent page. All other parameters must match the call that provided the page token."
+)
+
+// pagination names the fields of a paginated (AIP-158) method, for the x-pagination extension.
+type pagination struct {
+	PageSize      string `yaml:"pageSize"`
+	PageToken     string `yaml:"pageToken"`
+	NextPageToken string `yaml:"nextPageToken"`
+	Items         string `yaml:"items"`
+}
+
+// paginationForMethod finds the pagination fields of a method. GET methods are detected by
+// the AIP-158 field names, other methods have to use the openapi.pagination annotation.
+func (g *OpenAPIv3Generator) paginationForMethod(
+	methodName string,
+	inputMessage *protogen.Message,
+	outputMessage *protogen.Message,
+	options *open_api_extensions.Pagination,
+) *pagination {
+	declared := options != nil
+	if !declared {
+		if methodName != "GET" {
+			return nil
+		}
+		options = &open_api_extensions.Pagination{}
+	}
+	if options.GetDisabled() {
+		return nil
+	}
+
+	pageSize := g.findField(stringOr(options.PageSize, "page_size"), inputMessage)
+	pageToken := g.findField(stringOr(options.PageToken, "page_token"), inputMessage)
+	nextPageToken := g.findField(stringOr(options.NextPageToken, "next_page_token"), outputMessage)
+	var items *protogen.Field
+	if options.Items != nil {
+		items = g.findField(options.GetItems(), outputMessage)
+	} else {
+		for _, field := range outputMessage.Fields {
+			if field.Desc.IsList() && field.Desc.Kind() == protoreflect.MessageKind {
+				items = field
+				break
+			}
+		}
+	}
+
+	if pageSize == nil || pageToken == nil || nextPageToken == nil || items == nil || !items.Desc.IsList() {
+		if declared {
+			log.Printf("unable to find the pagination fields of %s", inputMessage.Desc.FullName())
+		}
+		return nil
+	}
+	return &pagination{
+		PageSize:      g.reflect.formatFieldName(pageSize.Desc),
+		PageToken:     g.reflect.formatFieldName(pageToken.Desc),
+		NextPageToken: g.reflect.formatFieldName(nextPageToken.Desc),
+		Items:         g.reflect.formatFieldName(items.Desc),
+	}
+}
+
+// addPaginationV3 adds the x-pagination extension to a paginated operation, and the standard
+// descriptions to its page size and token parameters if asked for.
+func (g *OpenAPIv3Generator) addPaginationV3(op *v3.Operation, p *pagination) {
+	op.SpecificationExtension = append(op.SpecificationExtension, &v3.NamedAny{
+		Name:  "x-pagination",
+		Value: newV3Any(p),
+	})
+	if g.conf.PaginationDescriptions == nil || !*g.conf.PaginationDescriptions {
+		return
+	}
+	for _, parameter := range op.Parameters {
+		param, ok := parameter.Oneof.(*v3.ParameterOrReference_Parameter)
+		if !ok || param.Parameter.Description != "" {
+			continue
+		}
+		switch param.Parameter.Name {
+		case p.PageSize:
+			param.Parameter.Description = pageSizeDescription
+		case p.PageToken:
+			param.Parameter.Description = pageTokenDescription
+		}
+	}
+}
+
+// stringOr returns the value of an optional string, or the fallback if it isn't set.
+func stringOr(value *string, fallback string) string {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
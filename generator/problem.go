@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"fmt"
+
+	wk "github.com/google/gnostic/cmd/protoc-gen-openapi/generator/wellknown"
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+const (
+	ErrorModelStatus  = "status"
+	ErrorModelProblem = "problem"
+
+	problemSchemaName = "Problem"
+	problemMediaType  = "application/problem+json"
+)
+
+// checkErrorModel checks the error_model option.
+func checkErrorModel(model string) error {
+	switch model {
+	case ErrorModelStatus, ErrorModelProblem:
+		return nil
+	}
+	return fmt.Errorf("invalid error_model %q: must be status or problem", model)
+}
+
+// problemErrorModel reports whether errors are RFC 7807 problem details.
+func (g *OpenAPIv3Generator) problemErrorModel() bool {
+	return g.conf.ErrorModel != nil && *g.conf.ErrorModel == ErrorModelProblem
+}
+
+// addErrorSchemaV3 adds the schema of errors to the document, and returns its name.
+func (g *OpenAPIv3Generator) addErrorSchemaV3(d *v3.Document) string {
+	if g.problemErrorModel() {
+		g.addSchemaToDocumentV3(d, g.problemSchema())
+		return problemSchemaName
+	}
+	return g.addStatusSchemaV3(d)
+}
+
+// addStatusSchemaV3 adds the google.rpc.Status schema to the document, and returns its name.
+func (g *OpenAPIv3Generator) addStatusSchemaV3(d *v3.Document) string {
+	anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+	g.addSchemaToDocumentV3(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
+	statusSchemaName := g.reflect.formatMessageName(statusProtoDesc)
+	g.addSchemaToDocumentV3(d, wk.NewGoogleRpcStatusSchema(statusSchemaName, anySchemaName))
+	return statusSchemaName
+}
+
+// problemSchema returns the RFC 7807 problem details schema, with the fields of the messages
+// annotated with openapi.problem_members as additional members.
+func (g *OpenAPIv3Generator) problemSchema() *v3.NamedSchemaOrReference {
+	stringSchema := func(format, description string) *v3.SchemaOrReference {
+		return &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{Type: "string", Format: format, Description: description}}}
+	}
+	properties := []*v3.NamedSchemaOrReference{
+		{Name: "type", Value: stringSchema("uri", "A URI reference that identifies the problem type.")},
+		{Name: "title", Value: stringSchema("", "A short, human-readable summary of the problem type.")},
+		{
+			Name: "status",
+			Value: &v3.SchemaOrReference{
+				Oneof: &v3.SchemaOrReference_Schema{
+					Schema: &v3.Schema{Type: "integer", Format: "int32", Description: "The HTTP status code generated by the origin server for this occurrence of the problem."}}},
+		},
+		{Name: "detail", Value: stringSchema("", "A human-readable explanation specific to this occurrence of the problem.")},
+		{Name: "instance", Value: stringSchema("uri-reference", "A URI reference that identifies the specific occurrence of the problem.")},
+	}
+	for _, file := range g.plugin.Files {
+		properties = append(properties, g.problemMembers(file.Messages)...)
+	}
+	return &v3.NamedSchemaOrReference{
+		Name: problemSchemaName,
+		Value: &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{
+					Type:        "object",
+					Description: "Problem details of an error, as defined by RFC 7807.",
+					Properties:  &v3.Properties{AdditionalProperties: properties},
+				},
+			},
+		},
+	}
+}
+
+// problemMembers returns the schemas of the fields of the messages annotated with
+// openapi.problem_members.
+func (g *OpenAPIv3Generator) problemMembers(messages []*protogen.Message) []*v3.NamedSchemaOrReference {
+	members := []*v3.NamedSchemaOrReference{}
+	for _, message := range messages {
+		members = append(members, g.problemMembers(message.Messages)...)
+		if !proto.GetExtension(message.Desc.Options(), open_api_extensions.E_ProblemMembers).(bool) {
+			continue
+		}
+		for _, field := range message.Fields {
+			fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+			if fieldSchema == nil {
+				continue
+			}
+			if schema, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Schema); ok {
+				schema.Schema.Description = g.filterCommentString(field.Comments.Leading, true)
+			}
+			members = append(members, &v3.NamedSchemaOrReference{
+				Name:  g.reflect.formatFieldName(field.Desc),
+				Value: fieldSchema,
+			})
+		}
+	}
+	return members
+}
@@ -0,0 +1,386 @@
+package generator
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	protovalidate "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// celRule is a CEL rule that can't be expressed with OpenAPI keywords. They are
+// listed in the x-cel extension so that consumers can still see them.
+type celRule struct {
+	ID         string      `yaml:"id,omitempty"`
+	Message    string      `yaml:"message,omitempty"`
+	Expression string      `yaml:"expression,omitempty"`
+	Value      interface{} `yaml:"value,omitempty"`
+}
+
+// protoValidateStringFormats maps protovalidate well known string rules to OpenAPI formats.
+// The formats match the ones used for protoc-gen-validate rules.
+var protoValidateStringFormats = map[string]string{
+	"email":    "email",
+	"hostname": "hostname",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"uri":      "uri",
+	"uri_ref":  "uri_ref",
+	"uuid":     "uuid",
+}
+
+// protoValidateFieldRules returns the buf.validate.field rules of a field, if any.
+func protoValidateFieldRules(field protoreflect.FieldDescriptor) *protovalidate.FieldRules {
+	if !proto.HasExtension(field.Options(), protovalidate.E_Field) {
+		return nil
+	}
+	fieldRules, ok := proto.GetExtension(field.Options(), protovalidate.E_Field).(*protovalidate.FieldRules)
+	if !ok {
+		return nil
+	}
+	return fieldRules
+}
+
+// addProtoValidateRules maps buf.validate (protovalidate) field rules onto the field schema.
+func (g *OpenAPIv3Generator) addProtoValidateRules(fieldSchema *v3.SchemaOrReference, field protoreflect.FieldDescriptor) {
+	fieldRules := protoValidateFieldRules(field)
+	if fieldRules == nil || fieldRules.GetIgnore() == protovalidate.Ignore_IGNORE_ALWAYS {
+		return
+	}
+	schema, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Schema)
+	if !ok {
+		return
+	}
+
+	if field.IsMap() {
+		cel := protoValidateCelRules(fieldRules)
+		if mapRules := fieldRules.GetMap(); mapRules != nil {
+			if mapRules.MinPairs != nil {
+				schema.Schema.MinProperties = int64(mapRules.GetMinPairs())
+			}
+			if mapRules.MaxPairs != nil {
+				schema.Schema.MaxProperties = int64(mapRules.GetMaxPairs())
+			}
+		}
+		addCelRules(schema.Schema, cel)
+		return
+	}
+
+	if field.IsList() {
+		cel := protoValidateCelRules(fieldRules)
+		repeatedRules := fieldRules.GetRepeated()
+		if repeatedRules == nil {
+			addCelRules(schema.Schema, cel)
+			return
+		}
+		if repeatedRules.MinItems != nil && fieldRules.GetIgnore() != protovalidate.Ignore_IGNORE_IF_ZERO_VALUE {
+			// an empty list is valid when it is ignored, so there is no lower bound
+			schema.Schema.MinItems = int64(repeatedRules.GetMinItems())
+		}
+		if repeatedRules.MaxItems != nil {
+			schema.Schema.MaxItems = int64(repeatedRules.GetMaxItems())
+		}
+		if repeatedRules.GetUnique() {
+			schema.Schema.UniqueItems = true
+		}
+		addCelRules(schema.Schema, cel)
+
+		// pull out the array items field rules
+		itemRules := repeatedRules.GetItems()
+		if itemRules == nil || itemRules.GetIgnore() == protovalidate.Ignore_IGNORE_ALWAYS {
+			return
+		}
+		itemSchema, ok := listItemsSchema(schema.Schema)
+		if !ok {
+			// message items are references, their rules belong to the referenced schema
+			return
+		}
+		protoValidateFieldRule(itemRules, field, itemSchema.Schema)
+		return
+	}
+
+	protoValidateFieldRule(fieldRules, field, schema.Schema)
+}
+
+// protoValidateFieldRule applies the type specific rules and the custom CEL rules to a schema.
+func protoValidateFieldRule(fieldRules *protovalidate.FieldRules, field protoreflect.FieldDescriptor, schema *v3.Schema) {
+	cel := protoValidateCelRules(fieldRules)
+
+	// The type specific rules live in the "type" oneof of FieldRules.
+	rules := fieldRules.ProtoReflect()
+	typeRules := rules.WhichOneof(rules.Descriptor().Oneofs().ByName("type"))
+	if typeRules != nil {
+		ignoreEmpty := fieldRules.GetIgnore() == protovalidate.Ignore_IGNORE_IF_ZERO_VALUE
+		cel = append(cel, protoValidateTypedRules(rules.Get(typeRules).Message(), field, schema, ignoreEmpty)...)
+	}
+
+	addCelRules(schema, cel)
+}
+
+// protoValidateTypedRules applies the standard rules of a rules message (e.g. StringRules) to a
+// schema and returns the predefined CEL rules of the ones that can't be expressed in OpenAPI.
+// With ignoreEmpty the zero value is valid, so lower length bounds are left out.
+func protoValidateTypedRules(rules protoreflect.Message, field protoreflect.FieldDescriptor, schema *v3.Schema, ignoreEmpty bool) []*celRule {
+	// Range doesn't guarantee an order, so go through the rules by field number.
+	var fields []protoreflect.FieldDescriptor
+	rules.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Number() < fields[j].Number()
+	})
+
+	set := map[string]bool{}
+	for _, fd := range fields {
+		set[string(fd.Name())] = true
+	}
+
+	var unmapped []*celRule
+	rulesName := rules.Descriptor().Name()
+	for _, fd := range fields {
+		v := rules.Get(fd)
+		var mapped bool
+		switch rulesName {
+		case "StringRules":
+			mapped = protoValidateStringRule(fd, v, schema, ignoreEmpty)
+		case "EnumRules":
+			mapped = protoValidateEnumRule(fd, v, field, schema)
+		case "BoolRules":
+			mapped = protoValidateConstRule(fd, v, schema)
+		case "FloatRules", "DoubleRules",
+			"Int32Rules", "Int64Rules", "UInt32Rules", "UInt64Rules",
+			"SInt32Rules", "SInt64Rules", "Fixed32Rules", "Fixed64Rules",
+			"SFixed32Rules", "SFixed64Rules":
+			mapped = protoValidateNumericRule(fd, v, schema)
+		}
+		if !mapped {
+			unmapped = append(unmapped, predefinedCelRules(fd, v, set)...)
+		}
+	}
+	return unmapped
+}
+
+func protoValidateStringRule(fd protoreflect.FieldDescriptor, v protoreflect.Value, schema *v3.Schema, ignoreEmpty bool) bool {
+	name := string(fd.Name())
+	if format, ok := protoValidateStringFormats[name]; ok {
+		if v.Bool() {
+			schema.Format = format
+		}
+		return true
+	}
+	switch name {
+	case "len":
+		if !ignoreEmpty {
+			schema.MinLength = int64(v.Uint())
+		}
+		schema.MaxLength = int64(v.Uint())
+	case "min_len":
+		if !ignoreEmpty {
+			schema.MinLength = int64(v.Uint())
+		}
+	case "max_len":
+		schema.MaxLength = int64(v.Uint())
+	case "pattern":
+		schema.Pattern = v.String()
+	default:
+		return protoValidateConstRule(fd, v, schema)
+	}
+	return true
+}
+
+func protoValidateEnumRule(fd protoreflect.FieldDescriptor, v protoreflect.Value, field protoreflect.FieldDescriptor, schema *v3.Schema) bool {
+	switch fd.Name() {
+	case "defined_only":
+		// we already list the set of valid enums
+	case "const":
+		schema.Enum = enumsToV3Any(field, int32(v.Int()))
+	case "in":
+		schema.Enum = enumsToV3Any(field, enumNumbers(v.List())...)
+	case "not_in":
+		schema.Enum = enumsToV3Any(field, remove(enumValues(field, false), enumNumbers(v.List())...)...)
+	case "example":
+		if v.List().Len() == 0 {
+			break
+		}
+		if ev := field.Enum().Values().ByNumber(protoreflect.EnumNumber(v.List().Get(0).Int())); ev != nil {
+			schema.Example = &v3.Any{Yaml: string(ev.Name())}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func protoValidateNumericRule(fd protoreflect.FieldDescriptor, v protoreflect.Value, schema *v3.Schema) bool {
+	switch fd.Name() {
+	case "gt":
+		setMinimum(schema, numericValue(v), true)
+	case "gte":
+		setMinimum(schema, numericValue(v), false)
+	case "lt":
+		setMaximum(schema, numericValue(v), true)
+	case "lte":
+		setMaximum(schema, numericValue(v), false)
+	default:
+		return protoValidateConstRule(fd, v, schema)
+	}
+	return true
+}
+
+// protoValidateConstRule handles the const, in and example rules that most rule types share.
+func protoValidateConstRule(fd protoreflect.FieldDescriptor, v protoreflect.Value, schema *v3.Schema) bool {
+	switch fd.Name() {
+	case "const":
+		schema.Enum = []*v3.Any{newV3Any(v.Interface())}
+	case "in":
+		list := v.List()
+		schema.Enum = make([]*v3.Any, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			schema.Enum = append(schema.Enum, newV3Any(list.Get(i).Interface()))
+		}
+	case "example":
+		if v.List().Len() > 0 {
+			schema.Example = newV3Any(v.List().Get(0).Interface())
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// protoValidateCelRules returns the custom CEL rules of a field.
+func protoValidateCelRules(fieldRules *protovalidate.FieldRules) []*celRule {
+	cel := []*celRule{}
+	for _, rule := range fieldRules.GetCel() {
+		cel = append(cel, &celRule{ID: rule.GetId(), Message: rule.GetMessage(), Expression: rule.GetExpression()})
+	}
+	for _, expression := range fieldRules.GetCelExpression() {
+		cel = append(cel, &celRule{Expression: expression})
+	}
+	return cel
+}
+
+// predefinedCelRules looks up the CEL rules that protovalidate defines for a standard rule.
+// Some rules have variants for combinations with other rules (e.g. int32.gt_lt), only
+// the variants for rules that are set are returned.
+func predefinedCelRules(fd protoreflect.FieldDescriptor, v protoreflect.Value, set map[string]bool) []*celRule {
+	predefined, ok := proto.GetExtension(fd.Options(), protovalidate.E_Predefined).(*protovalidate.PredefinedRules)
+	if !ok || predefined == nil {
+		// modifiers like string.strict don't have rules of their own
+		return nil
+	}
+	cel := []*celRule{}
+	value := celRuleValue(fd, v)
+	for _, rule := range predefined.GetCel() {
+		// ids look like "<type>.<rule>" or "<type>.<rule>_<other rule>[_exclusive]"
+		id := rule.GetId()
+		variant := strings.TrimPrefix(id[strings.Index(id, ".")+1:], string(fd.Name()))
+		if variant != "" {
+			other := strings.SplitN(strings.TrimPrefix(variant, "_"), "_", 2)[0]
+			if !set[other] {
+				continue
+			}
+		}
+		cel = append(cel, &celRule{ID: id, Message: rule.GetMessage(), Expression: rule.GetExpression(), Value: value})
+	}
+	return cel
+}
+
+// celRuleValue converts a rule value to something that can be marshalled to yaml.
+func celRuleValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	if fd.IsList() {
+		list := v.List()
+		values := make([]interface{}, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, celScalarValue(fd, list.Get(i)))
+		}
+		return values
+	}
+	return celScalarValue(fd, v)
+}
+
+func celScalarValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		// well known types like durations and timestamps use their JSON representation
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			log.Printf("unable to marshal rule value: %s", err)
+			return nil
+		}
+		var value interface{}
+		if err := yaml.Unmarshal(b, &value); err != nil {
+			return string(b)
+		}
+		return value
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.BytesKind:
+		return string(v.Bytes())
+	default:
+		return v.Interface()
+	}
+}
+
+// protoValidateRequired reports whether protovalidate requires a field to be set.
+func protoValidateRequired(field protoreflect.FieldDescriptor) bool {
+	fieldRules := protoValidateFieldRules(field)
+	return fieldRules.GetRequired() && fieldRules.GetIgnore() != protovalidate.Ignore_IGNORE_ALWAYS
+}
+
+// protoValidateOneofRequired reports whether a oneof has the buf.validate.oneof required rule.
+func protoValidateOneofRequired(oneof protoreflect.OneofDescriptor) bool {
+	if !proto.HasExtension(oneof.Options(), protovalidate.E_Oneof) {
+		return false
+	}
+	oneofRules, ok := proto.GetExtension(oneof.Options(), protovalidate.E_Oneof).(*protovalidate.OneofRules)
+	return ok && oneofRules.GetRequired()
+}
+
+// protoValidateMessageRules returns the buf.validate.message rules of a message, if any.
+func protoValidateMessageRules(message protoreflect.MessageDescriptor) *protovalidate.MessageRules {
+	if !proto.HasExtension(message.Options(), protovalidate.E_Message) {
+		return nil
+	}
+	messageRules, ok := proto.GetExtension(message.Options(), protovalidate.E_Message).(*protovalidate.MessageRules)
+	if !ok {
+		return nil
+	}
+	return messageRules
+}
+
+func enumNumbers(list protoreflect.List) []int32 {
+	numbers := make([]int32, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		numbers = append(numbers, int32(list.Get(i).Int()))
+	}
+	return numbers
+}
+
+func numericValue(v protoreflect.Value) float64 {
+	switch value := v.Interface().(type) {
+	case int32:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint32:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	case float64:
+		return value
+	}
+	return 0
+}
@@ -34,6 +34,10 @@ type OpenAPIv3Reflector struct {
 	conf Configuration
 
 	requiredSchemas []string // Names of schemas which are used through references.
+	input           bool     // Reference the input variants of messages. Kolla
+	update          bool     // Reference the partial variants of messages. Kolla
+
+	buildTags buildTagExpr // Build tag expression of the build_tag options, or nil. Kolla
 }
 
 // NewOpenAPIv3Reflector creates a new reflector.
@@ -124,12 +128,33 @@ func (r *OpenAPIv3Reflector) responseContentForMessage(message protoreflect.Mess
 
 func (r *OpenAPIv3Reflector) schemaReferenceForMessage(message protoreflect.MessageDescriptor) string {
 	schemaName := r.formatMessageName(message)
+	if r.update { // Kolla
+		schemaName += updateSchemaSuffix
+	} else if r.input && r.hasVariants(message) { // Kolla
+		schemaName += inputSchemaSuffix
+	}
 	if !contains(r.requiredSchemas, schemaName) {
 		r.requiredSchemas = append(r.requiredSchemas, schemaName)
 	}
 	return "#/components/schemas/" + schemaName
 }
 
+// schemaOrReferenceForInputMessage is schemaOrReferenceForMessage for request bodies, which
+// reference the input variants of messages. Kolla
+func (r *OpenAPIv3Reflector) schemaOrReferenceForInputMessage(message protoreflect.MessageDescriptor) *v3.SchemaOrReference {
+	r.input = true
+	defer func() { r.input = false }()
+	return r.schemaOrReferenceForMessage(message)
+}
+
+// schemaOrReferenceForUpdateMessage is schemaOrReferenceForMessage for the body of update
+// methods, which reference the partial variant of the message. Kolla
+func (r *OpenAPIv3Reflector) schemaOrReferenceForUpdateMessage(message protoreflect.MessageDescriptor) *v3.SchemaOrReference {
+	r.update = true
+	defer func() { r.update = false }()
+	return r.schemaOrReferenceForMessage(message)
+}
+
 // Returns a full schema for simple types, and a schema reference for complex types that reference
 // the definition in `#/components/schemas/`
 func (r *OpenAPIv3Reflector) schemaOrReferenceForMessage(message protoreflect.MessageDescriptor) *v3.SchemaOrReference {
@@ -234,6 +259,7 @@ func (r *OpenAPIv3Reflector) schemaOrReferenceForField(field protoreflect.FieldD
 
 	case protoreflect.EnumKind:
 		kindSchema = enumKindSchema(field) // Kolla custom behavior for enums
+		r.removeHiddenEnumValues(kindSchema, field)
 
 	case protoreflect.BoolKind:
 		kindSchema = wk.NewBooleanSchema()
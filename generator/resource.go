@@ -0,0 +1,329 @@
+package generator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+// DefaultResourceIDPattern is the pattern used for the variables of resource name patterns
+// when neither the resource_id_pattern option nor the openapi.resource_id annotation set one.
+const DefaultResourceIDPattern = "[a-z2-7]{26}"
+
+var resourcePatternVariableRX = regexp.MustCompile(`{([a-z_A-Z0-9]*)}`)
+
+// resourceDescriptor returns the google.api.resource annotation of a message, or nil.
+func resourceDescriptor(message *protogen.Message) *annotations.ResourceDescriptor {
+	extension := proto.GetExtension(message.Desc.Options(), annotations.E_Resource)
+	rule, ok := extension.(*annotations.ResourceDescriptor)
+	if !ok || rule == nil || len(rule.Pattern) == 0 {
+		return nil
+	}
+	return rule
+}
+
+// resourceNameField returns the name of the field holding the resource name.
+func resourceNameField(rule *annotations.ResourceDescriptor) string {
+	if rule.NameField != "" {
+		return rule.NameField
+	}
+	return "name"
+}
+
+// resourceNamePattern builds a regular expression matching every pattern of the resource.
+// Variables are replaced by the pattern configured for the variable, the resource type or
+// globally, in that order.
+func (g *OpenAPIv3Generator) resourceNamePattern(message *protogen.Message, rule *annotations.ResourceDescriptor) string {
+	patterns := make([]string, 0, len(rule.Pattern))
+	for _, pattern := range rule.Pattern {
+		var b strings.Builder
+		last := 0
+		for _, m := range resourcePatternVariableRX.FindAllStringSubmatchIndex(pattern, -1) {
+			b.WriteString(regexp.QuoteMeta(pattern[last:m[0]]))
+			b.WriteString(g.resourceVariablePattern(message, pattern[m[2]:m[3]]))
+			last = m[1]
+		}
+		b.WriteString(regexp.QuoteMeta(pattern[last:]))
+		patterns = append(patterns, b.String())
+	}
+	if len(patterns) == 1 {
+		return "^" + patterns[0] + "$"
+	}
+	return "^(?:" + strings.Join(patterns, "|") + ")$"
+}
+
+// resourceVariablePattern returns the pattern of a variable of the resource name patterns of a
+// message, configured for the variable, the resource type or globally, in that order. The message
+// is nil for resources defined with google.api.resource_definition.
+func (g *OpenAPIv3Generator) resourceVariablePattern(message *protogen.Message, variable string) string {
+	idPattern := DefaultResourceIDPattern
+	if g.conf.ResourceIDPattern != nil && *g.conf.ResourceIDPattern != "" {
+		idPattern = *g.conf.ResourceIDPattern
+	}
+	if message == nil {
+		return idPattern
+	}
+	if resourceID, ok := proto.GetExtension(message.Desc.Options(), open_api_extensions.E_ResourceId).(*open_api_extensions.ResourceID); ok && resourceID != nil {
+		if pattern, ok := resourceID.Variables[variable]; ok {
+			return pattern
+		}
+		if resourceID.Pattern != nil {
+			idPattern = resourceID.GetPattern()
+		}
+	}
+	return idPattern
+}
+
+// namedPathSegmentPatterns returns the patterns of the variables of a named path parameter like
+// {name=shelves/*/books/*}, from the resource name pattern of the field it is bound to. The
+// resource is the one the field names, either as the name field of a resource message or
+// through google.api.resource_reference. It returns nil if no pattern of the resource matches.
+func (g *OpenAPIv3Generator) namedPathSegmentPatterns(field *protogen.Field, starredPath string) []string {
+	if field == nil {
+		return nil
+	}
+	message, rule := field.Parent, resourceDescriptor(field.Parent)
+	if rule == nil || resourceNameField(rule) != string(field.Desc.Name()) {
+		reference := resourceReference(field)
+		if reference == nil || reference.Type == "" {
+			return nil
+		}
+		if message, rule = g.findResource(reference.Type); rule == nil {
+			return nil
+		}
+	}
+	for _, pattern := range rule.Pattern {
+		if starredResourcePattern(pattern) != starredPath {
+			continue
+		}
+		patterns := []string{}
+		for _, m := range resourcePatternVariableRX.FindAllStringSubmatch(pattern, -1) {
+			patterns = append(patterns, g.resourceVariablePattern(message, m[1]))
+		}
+		return patterns
+	}
+	return nil
+}
+
+// findResource finds the message or the google.api.resource_definition of a resource type.
+// Types that aren't fully qualified, like "Shelf", match the known type with the same name.
+// The message is nil for resource definitions.
+func (g *OpenAPIv3Generator) findResource(resourceType string) (*protogen.Message, *annotations.ResourceDescriptor) {
+	matches := func(rule *annotations.ResourceDescriptor) bool {
+		return rule.Type == resourceType || strings.HasSuffix(rule.Type, "/"+resourceType)
+	}
+	var findMessage func(messages []*protogen.Message) (*protogen.Message, *annotations.ResourceDescriptor)
+	findMessage = func(messages []*protogen.Message) (*protogen.Message, *annotations.ResourceDescriptor) {
+		for _, message := range messages {
+			if rule := resourceDescriptor(message); rule != nil && matches(rule) {
+				return message, rule
+			}
+			if message, rule := findMessage(message.Messages); rule != nil {
+				return message, rule
+			}
+		}
+		return nil, nil
+	}
+	for _, file := range g.plugin.Files {
+		if message, rule := findMessage(file.Messages); rule != nil {
+			return message, rule
+		}
+	}
+	for _, file := range g.plugin.Files {
+		definitions, _ := proto.GetExtension(file.Desc.Options(), annotations.E_ResourceDefinition).([]*annotations.ResourceDescriptor)
+		for _, rule := range definitions {
+			if matches(rule) {
+				return nil, rule
+			}
+		}
+	}
+	return nil, nil
+}
+
+// addResourceExtensions adds the resource type and its name patterns to the message schema.
+func addResourceExtensions(schema *v3.Schema, rule *annotations.ResourceDescriptor) {
+	if rule.Type != "" {
+		schema.SpecificationExtension = append(schema.SpecificationExtension, &v3.NamedAny{
+			Name:  "x-resource-type",
+			Value: newV3Any(rule.Type),
+		})
+	}
+	schema.SpecificationExtension = append(schema.SpecificationExtension, &v3.NamedAny{
+		Name:  "x-resource-patterns",
+		Value: newV3Any(rule.Pattern),
+	})
+}
+
+// resourceLinkResponse is a response whose resource reference fields are linked to Get operations.
+type resourceLinkResponse struct {
+	response *v3.Response
+	message  *protogen.Message
+}
+
+// resourceReference returns the google.api.resource_reference annotation of a field, or nil.
+func resourceReference(field *protogen.Field) *annotations.ResourceReference {
+	extension := proto.GetExtension(field.Desc.Options(), annotations.E_ResourceReference)
+	reference, ok := extension.(*annotations.ResourceReference)
+	if !ok || reference == nil || (reference.Type == "" && reference.ChildType == "") {
+		return nil
+	}
+	return reference
+}
+
+// addResourceReferenceExtension names the resource type referenced by a field in its schema.
+func addResourceReferenceExtension(schema *v3.Schema, reference *annotations.ResourceReference) {
+	if reference.Type != "" {
+		schema.SpecificationExtension = append(schema.SpecificationExtension, &v3.NamedAny{
+			Name:  "x-resource-reference",
+			Value: newV3Any(reference.Type),
+		})
+	}
+	if reference.ChildType != "" {
+		schema.SpecificationExtension = append(schema.SpecificationExtension, &v3.NamedAny{
+			Name:  "x-resource-child-reference",
+			Value: newV3Any(reference.ChildType),
+		})
+	}
+}
+
+// starredResourcePattern converts a resource pattern like "shelves/{shelf}" to the
+// form used in HTTP rules, "shelves/*".
+func starredResourcePattern(pattern string) string {
+	return resourcePatternVariableRX.ReplaceAllString(pattern, "*")
+}
+
+// resourcePatterns returns the patterns of every resource type known to the plugin,
+// from google.api.resource message options and google.api.resource_definition file options.
+func (g *OpenAPIv3Generator) resourcePatterns() map[string][]string {
+	patterns := make(map[string][]string)
+	var addMessages func(messages []*protogen.Message)
+	addMessages = func(messages []*protogen.Message) {
+		for _, message := range messages {
+			if rule := resourceDescriptor(message); rule != nil && rule.Type != "" {
+				patterns[rule.Type] = append(patterns[rule.Type], rule.Pattern...)
+			}
+			addMessages(message.Messages)
+		}
+	}
+	for _, file := range g.plugin.Files {
+		if definitions, ok := proto.GetExtension(file.Desc.Options(), annotations.E_ResourceDefinition).([]*annotations.ResourceDescriptor); ok {
+			for _, rule := range definitions {
+				patterns[rule.Type] = append(patterns[rule.Type], rule.Pattern...)
+			}
+		}
+		addMessages(file.Messages)
+	}
+	return patterns
+}
+
+// resourceTypePatterns finds the patterns of a resource type. Types that aren't fully
+// qualified, like "Shelf", match the known type with the same name.
+func resourceTypePatterns(patterns map[string][]string, resourceType string) []string {
+	if found, ok := patterns[resourceType]; ok {
+		return found
+	}
+	types := make([]string, 0, len(patterns))
+	for fullType := range patterns {
+		types = append(types, fullType)
+	}
+	sort.Strings(types)
+	for _, fullType := range types {
+		if strings.HasSuffix(fullType, "/"+resourceType) {
+			return patterns[fullType]
+		}
+	}
+	return nil
+}
+
+// linkTarget is an operation that links lead to, and its path parameter taking the whole
+// resource name, e.g. name for "/v1/{name}". Paths like "/v1/{name=shelves/*}" split the resource
+// name into segment parameters (shelf), which a link can't pass it to.
+type linkTarget struct {
+	operationID string
+	parameter   string
+}
+
+// link returns a link to the operation passing the resource name of the expression to its path
+// parameter, or naming it in x-resource-name if the path splits it into segment parameters.
+func (t linkTarget) link(expression string, description string) *v3.Link {
+	link := &v3.Link{
+		OperationId: t.operationID,
+		Description: description,
+	}
+	if t.parameter == "" {
+		link.SpecificationExtension = []*v3.NamedAny{
+			{Name: "x-resource-name", Value: newV3Any(expression)},
+		}
+		return link
+	}
+	link.Parameters = &v3.AnyOrExpression{
+		Oneof: &v3.AnyOrExpression_Expression{
+			Expression: &v3.Expression{
+				AdditionalProperties: []*v3.NamedAny{
+					{Name: "path." + t.parameter, Value: newV3Any(expression)},
+				},
+			},
+		},
+	}
+	return link
+}
+
+// pathNameParameter returns the path parameter of a path taking the whole resource name: its only
+// path parameter, like {name}, formatted like the parameter of the operation. Named path
+// parameters like {name=operations/*} are split into segment parameters, and have none.
+func (g *OpenAPIv3Generator) pathNameParameter(path string, inputMessage *protogen.Message) string {
+	if g.namedPathPattern.MatchString(path) {
+		return ""
+	}
+	if matches := g.pathPattern.FindAllStringSubmatch(path, -1); len(matches) == 1 {
+		return g.findAndFormatFieldName(matches[0][1], inputMessage)
+	}
+	return ""
+}
+
+// addResourceLinksV3 links the resource reference fields of responses to the Get
+// operation of the referenced resource type.
+func (g *OpenAPIv3Generator) addResourceLinksV3() {
+	if len(g.resourceLinkResponses) == 0 || len(g.resourceGetOperations) == 0 {
+		return
+	}
+	patterns := g.resourcePatterns()
+	for _, r := range g.resourceLinkResponses {
+		for _, field := range r.message.Fields {
+			reference := resourceReference(field)
+			if reference == nil || reference.Type == "" || field.Desc.IsList() || field.Desc.Kind() != protoreflect.StringKind {
+				continue
+			}
+			var target linkTarget
+			for _, pattern := range resourceTypePatterns(patterns, reference.Type) {
+				if t, ok := g.resourceGetOperations[starredResourcePattern(pattern)]; ok {
+					target = t
+					break
+				}
+			}
+			if target.operationID == "" {
+				continue
+			}
+			fieldName := g.reflect.formatFieldName(field.Desc)
+			if r.response.Links == nil {
+				r.response.Links = &v3.LinksOrReferences{}
+			}
+			r.response.Links.AdditionalProperties = append(r.response.Links.AdditionalProperties, &v3.NamedLinkOrReference{
+				Name: fieldName,
+				Value: &v3.LinkOrReference{
+					Oneof: &v3.LinkOrReference_Link{
+						Link: target.link("$response.body#/"+fieldName, "The `"+fieldName+"` field is the name of a "+reference.Type+" resource."),
+					},
+				},
+			})
+		}
+	}
+}
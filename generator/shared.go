@@ -0,0 +1,327 @@
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// outputDocument is a document and the file it is written to.
+type outputDocument struct {
+	file     string
+	document *v3.Document
+	included []string // Schemas of the include_messages option, kept even if unreferenced.
+}
+
+// sharedPackages parses the shared_packages option, a list of packages separated by semicolons.
+func sharedPackages(option string) []string {
+	packages := []string{}
+	for _, pkg := range strings.Split(option, ";") {
+		if pkg = strings.TrimSpace(pkg); pkg != "" {
+			packages = appendUnique(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// schemaPackage returns the proto package of the message of a schema, or "" for schemas that
+// aren't generated from messages.
+func (g *OpenAPIv3Generator) schemaPackage(name string) string {
+	if pkg, ok := g.schemaPackages[name]; ok {
+		return pkg
+	}
+	for _, desc := range []protoreflect.MessageDescriptor{statusProtoDesc, anyProtoDesc} {
+		if g.reflect.formatMessageName(desc) == name {
+			return string(desc.ParentFile().Package())
+		}
+	}
+	return ""
+}
+
+// shareSchemasV3 moves the schemas of the shared packages out of the documents, into a
+// components document per package in the directory of the output file. The documents reference
+// the shared schemas with relative file references, and the shared documents returned include
+// copies of the schemas of other packages they depend on.
+func (g *OpenAPIv3Generator) shareSchemasV3(documents []*outputDocument, outputFile string, packages []string) []*outputDocument {
+	sharedFile := func(pkg string) string {
+		return path.Join(path.Dir(outputFile), pkg+path.Ext(outputFile))
+	}
+	isShared := func(name string) bool {
+		return contains(packages, g.schemaPackage(name))
+	}
+
+	shared := map[string]*v3.Document{}
+	schemas := map[string]*v3.NamedSchemaOrReference{} // Unmodified schemas of the documents, by name.
+	for _, o := range documents {
+		local := []*v3.NamedSchemaOrReference{}
+		for _, schema := range o.document.Components.Schemas.AdditionalProperties {
+			if _, ok := schemas[schema.Name]; !ok {
+				schemas[schema.Name] = proto.Clone(schema).(*v3.NamedSchemaOrReference)
+			}
+			if !isShared(schema.Name) {
+				local = append(local, schema)
+				continue
+			}
+			pkg := g.schemaPackage(schema.Name)
+			if shared[pkg] == nil {
+				shared[pkg] = &v3.Document{
+					Openapi: "3.0.3",
+					Info:    &v3.Info{Title: pkg, Version: *g.conf.Version},
+					Paths:   &v3.Paths{},
+					Components: &v3.Components{
+						Schemas: &v3.SchemasOrReferences{AdditionalProperties: []*v3.NamedSchemaOrReference{}},
+					},
+				}
+			}
+			addSchemaOnce(shared[pkg], schema)
+		}
+		o.document.Components.Schemas.AdditionalProperties = local
+		g.referenceSharedSchemasV3(o.document, o.file, isShared, sharedFile)
+		pruneSchemasV3(o.document, o.included...)
+	}
+
+	names := make([]string, 0, len(shared))
+	for pkg := range shared {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+	sharedDocuments := []*outputDocument{}
+	for _, pkg := range names {
+		d := shared[pkg]
+		// Copy the schemas of packages that aren't shared, until the document is complete.
+		for added := true; added; {
+			added = false
+			for _, name := range localReferences(d) {
+				if schema, ok := schemas[name]; ok && !isShared(name) && addSchemaOnce(d, proto.Clone(schema).(*v3.NamedSchemaOrReference)) {
+					added = true
+				}
+			}
+		}
+		g.referenceSharedSchemasV3(d, sharedFile(pkg), isShared, sharedFile)
+		sortSchemas(d)
+		sharedDocuments = append(sharedDocuments, &outputDocument{file: sharedFile(pkg), document: d})
+	}
+	return sharedDocuments
+}
+
+// referenceSharedSchemasV3 replaces the local references to shared schemas of other files with
+// relative file references.
+func (g *OpenAPIv3Generator) referenceSharedSchemasV3(d *v3.Document, file string, isShared func(string) bool, sharedFile func(string) string) {
+	walkReferences(d.ProtoReflect(), func(ref *v3.Reference) {
+		if !strings.HasPrefix(ref.XRef, schemaRefPrefix) {
+			return
+		}
+		name := strings.TrimPrefix(ref.XRef, schemaRefPrefix)
+		if !isShared(name) {
+			return
+		}
+		if target := sharedFile(g.schemaPackage(name)); target != file {
+			ref.XRef = relativeFile(file, target) + ref.XRef
+		}
+	})
+}
+
+// bundleDocumentsV3 flattens documents referencing each other into a single document, with the
+// paths, tags, servers and schemas of all the documents, and local references only.
+func (g *OpenAPIv3Generator) bundleDocumentsV3(documents []*outputDocument, shared []*outputDocument) *v3.Document {
+	d := &v3.Document{
+		Openapi: "3.0.3",
+		Info: &v3.Info{
+			Version:     *g.conf.Version,
+			Title:       *g.conf.Title,
+			Description: *g.conf.Description,
+		},
+		Paths: &v3.Paths{},
+		Components: &v3.Components{
+			Schemas: &v3.SchemasOrReferences{AdditionalProperties: []*v3.NamedSchemaOrReference{}},
+		},
+	}
+	if len(documents) == 1 {
+		d.Info = proto.Clone(documents[0].document.Info).(*v3.Info)
+	}
+	for _, o := range append(append([]*outputDocument{}, documents...), shared...) {
+		source := proto.Clone(o.document).(*v3.Document)
+		for _, server := range source.Servers {
+			if !containsServer(d.Servers, server.Url) {
+				d.Servers = append(d.Servers, server)
+			}
+		}
+		for _, tag := range source.Tags {
+			if !containsTag(d.Tags, tag.Name) {
+				d.Tags = append(d.Tags, tag)
+			}
+		}
+		for _, pathItem := range source.Paths.GetPath() {
+			if existing := findPath(d.Paths, pathItem.Name); existing != nil {
+				proto.Merge(existing.Value, pathItem.Value)
+			} else {
+				d.Paths.Path = append(d.Paths.Path, pathItem)
+			}
+		}
+		if source.Components.GetSecuritySchemes() != nil {
+			if d.Components.SecuritySchemes == nil {
+				d.Components.SecuritySchemes = &v3.SecuritySchemesOrReferences{}
+			}
+			for _, scheme := range source.Components.SecuritySchemes.AdditionalProperties {
+				if !containsSecurityScheme(d.Components.SecuritySchemes, scheme.Name) {
+					d.Components.SecuritySchemes.AdditionalProperties = append(d.Components.SecuritySchemes.AdditionalProperties, scheme)
+				}
+			}
+		}
+		for _, schema := range source.Components.GetSchemas().GetAdditionalProperties() {
+			addSchemaOnce(d, schema)
+		}
+	}
+
+	walkReferences(d.ProtoReflect(), func(ref *v3.Reference) {
+		if i := strings.Index(ref.XRef, "#"); i > 0 {
+			ref.XRef = ref.XRef[i:]
+		}
+	})
+
+	sort.Slice(d.Tags, func(i, j int) bool {
+		return d.Tags[i].Name < d.Tags[j].Name
+	})
+	sort.Slice(d.Paths.Path, func(i, j int) bool {
+		return d.Paths.Path[i].Name < d.Paths.Path[j].Name
+	})
+	sortSchemas(d)
+	return d
+}
+
+// pruneSchemasV3 removes the schemas that aren't referenced by the document, directly or
+// through other schemas, except for the named schemas and the schemas they reference.
+func pruneSchemasV3(d *v3.Document, names ...string) {
+	schemas := d.Components.Schemas
+	d.Components.Schemas = nil
+	reachable := map[string]bool{}
+	queue := append(localReferences(d), names...)
+	d.Components.Schemas = schemas
+
+	byName := map[string]*v3.NamedSchemaOrReference{}
+	for _, schema := range schemas.AdditionalProperties {
+		byName[schema.Name] = schema
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reachable[name] || byName[name] == nil {
+			continue
+		}
+		reachable[name] = true
+		queue = append(queue, localReferences(byName[name])...)
+	}
+
+	kept := []*v3.NamedSchemaOrReference{}
+	for _, schema := range schemas.AdditionalProperties {
+		if reachable[schema.Name] {
+			kept = append(kept, schema)
+		}
+	}
+	schemas.AdditionalProperties = kept
+}
+
+// localReferences returns the names of the component schemas referenced in a message of the
+// document, without the references to other files.
+func localReferences(m proto.Message) []string {
+	names := []string{}
+	walkReferences(m.ProtoReflect(), func(ref *v3.Reference) {
+		if strings.HasPrefix(ref.XRef, schemaRefPrefix) {
+			names = appendUnique(names, strings.TrimPrefix(ref.XRef, schemaRefPrefix))
+		}
+	})
+	return names
+}
+
+// walkReferences calls f with the references in a message of the document.
+func walkReferences(m protoreflect.Message, f func(*v3.Reference)) {
+	if ref, ok := m.Interface().(*v3.Reference); ok {
+		f(ref)
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			for i := 0; i < v.List().Len(); i++ {
+				walkReferences(v.List().Get(i).Message(), f)
+			}
+		} else {
+			walkReferences(v.Message(), f)
+		}
+		return true
+	})
+}
+
+// relativeFile returns the path of a file relative to the directory of another file. Both paths
+// are relative to the output directory.
+func relativeFile(from string, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+	if err != nil {
+		return to
+	}
+	return filepath.ToSlash(rel)
+}
+
+// addSchemaOnce adds a schema to the components of a document, unless it has a schema with the
+// same name. It reports whether the schema was added.
+func addSchemaOnce(d *v3.Document, schema *v3.NamedSchemaOrReference) bool {
+	for _, s := range d.Components.Schemas.AdditionalProperties {
+		if s.Name == schema.Name {
+			return false
+		}
+	}
+	d.Components.Schemas.AdditionalProperties = append(d.Components.Schemas.AdditionalProperties, schema)
+	return true
+}
+
+func sortSchemas(d *v3.Document) {
+	pairs := d.Components.Schemas.AdditionalProperties
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Name < pairs[j].Name
+	})
+}
+
+func containsServer(servers []*v3.Server, url string) bool {
+	for _, server := range servers {
+		if server.Url == url {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTag(tags []*v3.Tag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSecurityScheme(schemes *v3.SecuritySchemesOrReferences, name string) bool {
+	for _, scheme := range schemes.AdditionalProperties {
+		if scheme.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func findPath(paths *v3.Paths, name string) *v3.NamedPathItem {
+	for _, pathItem := range paths.Path {
+		if pathItem.Name == name {
+			return pathItem
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	StreamingContentGrpcGateway = "grpc_gateway"
+	StreamingContentNDJSON      = "ndjson"
+	StreamingContentSSE         = "sse"
+
+	ClientStreamingDocument = "document"
+	ClientStreamingExclude  = "exclude"
+
+	clientStreamingNote = "Note: this method streams requests, which OpenAPI cannot describe. The request body is a newline-delimited stream of the request schema."
+)
+
+// checkStreamingOptions checks the streaming_content and client_streaming options.
+func checkStreamingOptions(content string, clientStreaming string) error {
+	switch content {
+	case StreamingContentGrpcGateway, StreamingContentNDJSON, StreamingContentSSE:
+	default:
+		return fmt.Errorf("invalid streaming_content %q: must be grpc_gateway, ndjson or sse", content)
+	}
+	switch clientStreaming {
+	case ClientStreamingDocument, ClientStreamingExclude:
+	default:
+		return fmt.Errorf("invalid client_streaming %q: must be document or exclude", clientStreaming)
+	}
+	return nil
+}
+
+// isClientStreaming reports whether a method is client or bidi streaming.
+func isClientStreaming(method *protogen.Method) bool {
+	return method.Desc.IsStreamingClient()
+}
+
+// streamingDirection returns the x-streaming value of a method, or "" for unary methods.
+func streamingDirection(method *protogen.Method) string {
+	switch {
+	case method.Desc.IsStreamingClient() && method.Desc.IsStreamingServer():
+		return "bidi"
+	case method.Desc.IsStreamingClient():
+		return "client"
+	case method.Desc.IsStreamingServer():
+		return "server"
+	}
+	return ""
+}
+
+// addStreamingV3 documents a streaming method: the x-streaming extension, the content of
+// server streaming responses, and a note on client streaming requests.
+func (g *OpenAPIv3Generator) addStreamingV3(d *v3.Document, op *v3.Operation, method *protogen.Method) {
+	direction := streamingDirection(method)
+	if direction == "" {
+		return
+	}
+	op.SpecificationExtension = append(op.SpecificationExtension, &v3.NamedAny{
+		Name:  "x-streaming",
+		Value: newV3Any(direction),
+	})
+
+	if method.Desc.IsStreamingClient() {
+		if op.Description != "" {
+			op.Description += "\n\n"
+		}
+		op.Description += clientStreamingNote
+	}
+
+	if !method.Desc.IsStreamingServer() {
+		return
+	}
+	response, ok := op.Responses.ResponseOrReference[0].Value.Oneof.(*v3.ResponseOrReference_Response)
+	if !ok || response.Response.Content == nil || len(response.Response.Content.AdditionalProperties) == 0 {
+		return
+	}
+	schema := response.Response.Content.AdditionalProperties[0].Value.Schema
+
+	var mediaType string
+	switch *g.conf.StreamingContent {
+	case StreamingContentNDJSON:
+		mediaType = "application/x-ndjson"
+	case StreamingContentSSE:
+		mediaType = "text/event-stream"
+	default:
+		// grpc-gateway streams newline-delimited objects with either a result or a
+		// google.rpc.Status error, whatever the error model of the other responses.
+		mediaType = "application/json"
+		errorSchemaName := g.addStatusSchemaV3(d)
+		schema = &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{
+					Type: "object",
+					Properties: &v3.Properties{
+						AdditionalProperties: []*v3.NamedSchemaOrReference{
+							{Name: "result", Value: schema},
+							{
+								Name: "error",
+								Value: &v3.SchemaOrReference{
+									Oneof: &v3.SchemaOrReference_Reference{
+										Reference: &v3.Reference{XRef: "#/components/schemas/" + errorSchemaName}}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	response.Response.Description = "A stream of responses."
+	response.Response.Content = &v3.MediaTypes{
+		AdditionalProperties: []*v3.NamedMediaType{
+			{Name: mediaType, Value: &v3.MediaType{Schema: schema}},
+		},
+	}
+}
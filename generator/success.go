@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+// successResponses returns the success responses of a method: the ones declared with the
+// openapi.success_responses annotation, or the AIP status codes if the option is set.
+func (g *OpenAPIv3Generator) successResponses(method *protogen.Method, methodName string) []*open_api_extensions.SuccessResponse {
+	if options, ok := proto.GetExtension(method.Desc.Options(), open_api_extensions.E_SuccessResponses).(*open_api_extensions.SuccessResponses); ok && options != nil {
+		return options.GetResponses()
+	}
+	if g.conf.SuccessStatusCodes == nil || !*g.conf.SuccessStatusCodes {
+		return nil
+	}
+	switch {
+	case method.Output.Desc.FullName() == "google.protobuf.Empty":
+		return []*open_api_extensions.SuccessResponse{{Status: proto.Int32(http.StatusNoContent), Empty: proto.Bool(true)}}
+	case methodName == "POST" && strings.HasPrefix(string(method.Desc.Name()), "Create"):
+		return []*open_api_extensions.SuccessResponse{{Status: proto.Int32(http.StatusCreated)}}
+	}
+	return nil
+}
+
+// setSuccessResponsesV3 replaces the "200" response of an operation with the success responses
+// of the method. The responses have the content of the "200" response, unless they are empty.
+func (g *OpenAPIv3Generator) setSuccessResponsesV3(op *v3.Operation, method *protogen.Method, methodName string) {
+	successResponses := g.successResponses(method, methodName)
+	if len(successResponses) == 0 {
+		return
+	}
+	first, ok := op.Responses.ResponseOrReference[0].Value.Oneof.(*v3.ResponseOrReference_Response)
+	if !ok {
+		return
+	}
+
+	responses := []*v3.NamedResponseOrReference{}
+	for _, s := range successResponses {
+		status := int(s.GetStatus())
+		if status < 200 || status > 299 {
+			log.Printf("invalid success status %d of %s", status, op.OperationId)
+			continue
+		}
+		response := &v3.Response{
+			Description: s.GetDescription(),
+			Content:     first.Response.Content,
+		}
+		if response.Description == "" {
+			response.Description = http.StatusText(status)
+		}
+		if s.GetEmpty() {
+			response.Content = nil
+		}
+		responses = append(responses, &v3.NamedResponseOrReference{
+			Name: strconv.Itoa(status),
+			Value: &v3.ResponseOrReference{
+				Oneof: &v3.ResponseOrReference_Response{Response: response},
+			},
+		})
+	}
+	if len(responses) == 0 {
+		return
+	}
+	op.Responses.ResponseOrReference = append(responses, op.Responses.ResponseOrReference[1:]...)
+}
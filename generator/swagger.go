@@ -0,0 +1,298 @@
+package generator
+
+import (
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Swagger 2.0 can't express some OpenAPI 3 constructs, which are kept as extensions:
+//   - oneOf, anyOf and not become x-oneOf, x-anyOf and x-not, leaving the schema unconstrained.
+//   - nullable, writeOnly and deprecated schemas become x-nullable, x-writeOnly and x-deprecated.
+//   - Parameter examples become x-example.
+//   - Links, callbacks and operation servers are dropped.
+
+const swaggerVersion = "2.0"
+
+var swaggerOperations = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// convertToSwagger converts a rendered OpenAPI 3.0 document to a Swagger 2.0 document.
+func convertToSwagger(document *yaml.Node) *yaml.Node {
+	swagger := &yaml.Node{Kind: yaml.MappingNode}
+	addMappingPair(swagger, "swagger", yamlString(swaggerVersion))
+	if info := mappingValue(document, "info"); info != nil {
+		addMappingPair(swagger, "info", info)
+	}
+	if servers := mappingValue(document, "servers"); servers != nil && len(servers.Content) > 0 {
+		if u, err := url.Parse(mappingValue(servers.Content[0], "url").Value); err == nil && u.Host != "" {
+			addMappingPair(swagger, "host", yamlString(u.Host))
+			if u.Path != "" && u.Path != "/" {
+				addMappingPair(swagger, "basePath", yamlString(u.Path))
+			}
+			if u.Scheme != "" {
+				addMappingPair(swagger, "schemes", yamlStrings(u.Scheme))
+			}
+		}
+	}
+	addMappingPair(swagger, "consumes", yamlStrings("application/json"))
+	addMappingPair(swagger, "produces", yamlStrings("application/json"))
+
+	paths := &yaml.Node{Kind: yaml.MappingNode}
+	if v3Paths := mappingValue(document, "paths"); v3Paths != nil {
+		for i := 0; i+1 < len(v3Paths.Content); i += 2 {
+			addMappingPair(paths, v3Paths.Content[i].Value, convertPathItemToSwagger(v3Paths.Content[i+1]))
+		}
+	}
+	addMappingPair(swagger, "paths", paths)
+
+	if schemas := mappingValue(mappingValue(document, "components"), "schemas"); schemas != nil && len(schemas.Content) > 0 {
+		for i := 1; i < len(schemas.Content); i += 2 {
+			convertSchemaToSwagger(schemas.Content[i])
+		}
+		addMappingPair(swagger, "definitions", schemas)
+	}
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if key := document.Content[i].Value; key == "tags" || strings.HasPrefix(key, "x-") {
+			addMappingPair(swagger, key, document.Content[i+1])
+		}
+	}
+
+	rewriteSwaggerReferences(swagger)
+	return swagger
+}
+
+// convertPathItemToSwagger converts the operations and parameters of a path.
+func convertPathItemToSwagger(pathItem *yaml.Node) *yaml.Node {
+	item := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		key, value := pathItem.Content[i].Value, pathItem.Content[i+1]
+		switch {
+		case contains(swaggerOperations, key):
+			addMappingPair(item, key, convertOperationToSwagger(value))
+		case key == "parameters":
+			addMappingPair(item, key, convertParametersToSwagger(value))
+		case strings.HasPrefix(key, "x-"):
+			addMappingPair(item, key, value)
+		}
+	}
+	return item
+}
+
+// convertOperationToSwagger converts an operation, with its request body as a body parameter.
+func convertOperationToSwagger(op *yaml.Node) *yaml.Node {
+	operation := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range []string{"tags", "summary", "description", "operationId"} {
+		if value := mappingValue(op, key); value != nil {
+			addMappingPair(operation, key, value)
+		}
+	}
+
+	parameters := &yaml.Node{Kind: yaml.SequenceNode}
+	if v3Parameters := mappingValue(op, "parameters"); v3Parameters != nil {
+		parameters = convertParametersToSwagger(v3Parameters)
+	}
+	if requestBody := mappingValue(op, "requestBody"); requestBody != nil {
+		mediaType, content := swaggerMediaType(mappingValue(requestBody, "content"))
+		if mediaType != "" && mediaType != "application/json" {
+			addMappingPair(operation, "consumes", yamlStrings(mediaType))
+		}
+		body := &yaml.Node{Kind: yaml.MappingNode}
+		addMappingPair(body, "name", yamlString("body"))
+		addMappingPair(body, "in", yamlString("body"))
+		if description := mappingValue(requestBody, "description"); description != nil {
+			addMappingPair(body, "description", description)
+		}
+		if required := mappingValue(requestBody, "required"); required != nil {
+			addMappingPair(body, "required", required)
+		}
+		if schema := mappingValue(content, "schema"); schema != nil {
+			convertSchemaToSwagger(schema)
+			addMappingPair(body, "schema", schema)
+		}
+		parameters.Content = append(parameters.Content, body)
+	}
+
+	responses := &yaml.Node{Kind: yaml.MappingNode}
+	produces := []string{}
+	if v3Responses := mappingValue(op, "responses"); v3Responses != nil {
+		for i := 0; i+1 < len(v3Responses.Content); i += 2 {
+			response, mediaTypes := convertResponseToSwagger(v3Responses.Content[i+1])
+			addMappingPair(responses, v3Responses.Content[i].Value, response)
+			for _, mediaType := range mediaTypes {
+				produces = appendUnique(produces, mediaType)
+			}
+		}
+	}
+	if len(produces) > 1 || (len(produces) == 1 && produces[0] != "application/json") {
+		addMappingPair(operation, "produces", yamlStrings(produces...))
+	}
+	if len(parameters.Content) > 0 {
+		addMappingPair(operation, "parameters", parameters)
+	}
+	addMappingPair(operation, "responses", responses)
+
+	for i := 0; i+1 < len(op.Content); i += 2 {
+		if key := op.Content[i].Value; key == "deprecated" || strings.HasPrefix(key, "x-") {
+			addMappingPair(operation, key, op.Content[i+1])
+		}
+	}
+	return operation
+}
+
+// convertParametersToSwagger converts parameters, whose schemas become the type of the parameter.
+func convertParametersToSwagger(v3Parameters *yaml.Node) *yaml.Node {
+	parameters := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, p := range v3Parameters.Content {
+		parameter := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range []string{"name", "in", "description", "required"} {
+			if value := mappingValue(p, key); value != nil {
+				addMappingPair(parameter, key, value)
+			}
+		}
+		addSwaggerType(parameter, mappingValue(p, "schema"))
+		for i := 0; i+1 < len(p.Content); i += 2 {
+			switch key := p.Content[i].Value; {
+			case key == "example" || key == "deprecated":
+				addMappingPair(parameter, "x-"+key, p.Content[i+1])
+			case strings.HasPrefix(key, "x-"):
+				addMappingPair(parameter, key, p.Content[i+1])
+			}
+		}
+		parameters.Content = append(parameters.Content, parameter)
+	}
+	return parameters
+}
+
+// addSwaggerType adds the type of a schema to a parameter, items or header. References and
+// objects can't be parameters, and are strings.
+func addSwaggerType(parameter *yaml.Node, schema *yaml.Node) {
+	if schema == nil || mappingIndex(schema, "$ref") >= 0 || mappingIndex(schema, "type") < 0 {
+		addMappingPair(parameter, "type", yamlString("string"))
+		return
+	}
+	convertSchemaToSwagger(schema)
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i].Value, schema.Content[i+1]
+		switch key {
+		case "description", "properties", "additionalProperties", "allOf", "readOnly", "x-oneOf", "x-anyOf", "x-not":
+			continue
+		case "items":
+			items := &yaml.Node{Kind: yaml.MappingNode}
+			addSwaggerType(items, value)
+			value = items
+		case "example":
+			key = "x-example"
+		}
+		addMappingPair(parameter, key, value)
+	}
+	if mappingValue(parameter, "type").Value == "array" && mappingIndex(parameter, "in") >= 0 {
+		addMappingPair(parameter, "collectionFormat", yamlString("multi"))
+	}
+}
+
+// convertResponseToSwagger converts a response, and returns its media types.
+func convertResponseToSwagger(v3Response *yaml.Node) (*yaml.Node, []string) {
+	response := &yaml.Node{Kind: yaml.MappingNode}
+	if description := mappingValue(v3Response, "description"); description != nil {
+		addMappingPair(response, "description", description)
+	}
+	mediaTypes := []string{}
+	if content := mappingValue(v3Response, "content"); content != nil {
+		for i := 0; i < len(content.Content); i += 2 {
+			mediaTypes = append(mediaTypes, content.Content[i].Value)
+		}
+		if _, mediaType := swaggerMediaType(content); mediaType != nil {
+			if schema := mappingValue(mediaType, "schema"); schema != nil {
+				convertSchemaToSwagger(schema)
+				addMappingPair(response, "schema", schema)
+			}
+		}
+	}
+	if v3Headers := mappingValue(v3Response, "headers"); v3Headers != nil {
+		headers := &yaml.Node{Kind: yaml.MappingNode}
+		for i := 0; i+1 < len(v3Headers.Content); i += 2 {
+			header := &yaml.Node{Kind: yaml.MappingNode}
+			if description := mappingValue(v3Headers.Content[i+1], "description"); description != nil {
+				addMappingPair(header, "description", description)
+			}
+			addSwaggerType(header, mappingValue(v3Headers.Content[i+1], "schema"))
+			addMappingPair(headers, v3Headers.Content[i].Value, header)
+		}
+		addMappingPair(response, "headers", headers)
+	}
+	for i := 0; i+1 < len(v3Response.Content); i += 2 {
+		if key := v3Response.Content[i].Value; strings.HasPrefix(key, "x-") {
+			addMappingPair(response, key, v3Response.Content[i+1])
+		}
+	}
+	return response, mediaTypes
+}
+
+// swaggerMediaType returns the JSON media type of a content, or its first media type.
+func swaggerMediaType(content *yaml.Node) (string, *yaml.Node) {
+	if content == nil || len(content.Content) < 2 {
+		return "", nil
+	}
+	if mediaType := mappingValue(content, "application/json"); mediaType != nil {
+		return "application/json", mediaType
+	}
+	return content.Content[0].Value, content.Content[1]
+}
+
+// convertSchemaToSwagger keeps the OpenAPI 3 keywords of a schema that Swagger 2.0 doesn't have
+// as extensions.
+func convertSchemaToSwagger(schema *yaml.Node) {
+	if schema.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i].Value, schema.Content[i+1]
+		switch key {
+		case "nullable", "writeOnly", "deprecated":
+			schema.Content[i] = yamlString("x-" + key)
+		case "oneOf", "anyOf":
+			schema.Content[i] = yamlString("x-" + key)
+			for _, item := range value.Content {
+				convertSchemaToSwagger(item)
+			}
+		case "not":
+			schema.Content[i] = yamlString("x-" + key)
+			convertSchemaToSwagger(value)
+		case "properties":
+			for j := 1; j < len(value.Content); j += 2 {
+				convertSchemaToSwagger(value.Content[j])
+			}
+		case "items", "additionalProperties":
+			convertSchemaToSwagger(value)
+		case "allOf":
+			for _, item := range value.Content {
+				convertSchemaToSwagger(item)
+			}
+		}
+	}
+}
+
+// rewriteSwaggerReferences references definitions instead of component schemas.
+func rewriteSwaggerReferences(node *yaml.Node) {
+	for i, child := range node.Content {
+		if node.Kind == yaml.MappingNode && i%2 == 1 && node.Content[i-1].Value == "$ref" {
+			child.Value = strings.Replace(child.Value, "#/components/schemas/", "#/definitions/", 1)
+			continue
+		}
+		rewriteSwaggerReferences(child)
+	}
+}
+
+// addMappingPair appends a key and a value to a mapping node.
+func addMappingPair(node *yaml.Node, key string, value *yaml.Node) {
+	node.Content = append(node.Content, yamlString(key), value)
+}
+
+func yamlStrings(values ...string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, value := range values {
+		node.Content = append(node.Content, yamlString(value))
+	}
+	return node
+}
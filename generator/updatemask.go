@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// updateMaskField returns the google.protobuf.FieldMask field of the request of an update
+// method, which is a PATCH method with a field mask (AIP-134), or nil.
+func updateMaskField(methodName string, inputMessage *protogen.Message) *protogen.Field {
+	if methodName != "PATCH" {
+		return nil
+	}
+	for _, field := range inputMessage.Fields {
+		if field.Message != nil && !field.Desc.IsList() && field.Message.Desc.FullName() == "google.protobuf.FieldMask" {
+			return field
+		}
+	}
+	return nil
+}
+
+// updateMaskPaths lists the field paths of a message that can be updated. Paths go into
+// message fields as long as the field hasn't been seen CircularDepth times.
+func (g *OpenAPIv3Generator) updateMaskPaths(message *protogen.Message, prefix string, depths map[string]int) []string {
+	paths := []string{}
+	for _, field := range message.Fields {
+		if fieldReadOnly(field.Desc) || hasFieldBehavior(field.Desc, annotations.FieldBehavior_IMMUTABLE) {
+			continue
+		}
+		path := prefix + g.reflect.formatFieldName(field.Desc)
+		paths = append(paths, path)
+
+		// Lists, maps and well known types are replaced as a whole.
+		if field.Message == nil || field.Desc.IsList() || field.Desc.IsMap() || isWellKnownMessage(field.Message) {
+			continue
+		}
+		fieldFullName := string(field.Desc.FullName())
+		if depths[fieldFullName] < *g.conf.CircularDepth {
+			depths[fieldFullName]++
+			paths = append(paths, g.updateMaskPaths(field.Message, path+".", depths)...)
+			depths[fieldFullName]--
+		}
+	}
+	return paths
+}
+
+// isWellKnownMessage reports whether a message is one of the google.protobuf or google.type
+// messages, which have their own JSON representation.
+func isWellKnownMessage(message *protogen.Message) bool {
+	pkg := string(message.Desc.ParentFile().Package())
+	return pkg == "google.protobuf" || pkg == "google.type"
+}
+
+// updateMaskDescription adds the valid field paths to the description of the update mask.
+func updateMaskDescription(description string, paths []string) string {
+	if len(paths) == 0 {
+		return description
+	}
+	if description != "" {
+		description += " "
+	}
+	return description + "Valid field paths: `" + strings.Join(paths, "`, `") + "`."
+}
@@ -5,11 +5,26 @@ import (
 
 	"github.com/envoyproxy/protoc-gen-validate/validate"
 	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
 )
 
+// addValidationRules maps protoc-gen-validate and protovalidate field rules onto the field schema.
+// Both can be used in the same file, and map the same rules to the same OpenAPI keywords.
 func (g *OpenAPIv3Generator) addValidationRules(fieldSchema *v3.SchemaOrReference, field protoreflect.FieldDescriptor) {
+	g.addPGVRules(fieldSchema, field)
+	g.addProtoValidateRules(fieldSchema, field)
+	// The rules can list the hidden enum values again.
+	g.reflect.removeHiddenEnumValues(fieldSchema, field)
+}
+
+// addPGVRules maps protoc-gen-validate field rules onto the field schema.
+func (g *OpenAPIv3Generator) addPGVRules(fieldSchema *v3.SchemaOrReference, field protoreflect.FieldDescriptor) {
+	if pgvDisabled(field.ContainingMessage()) {
+		return
+	}
 	validationRules := proto.GetExtension(field.Options(), validate.E_Rules)
 	if validationRules == nil {
 		return
@@ -46,12 +61,16 @@ func (g *OpenAPIv3Generator) addValidationRules(fieldSchema *v3.SchemaOrReferenc
 		// unless skip is specified here.
 		// IgnoreEmpty specifies that the validation rules of this field should be
 		// evaluated only if the field is not empty
-		if repeatedRules.MinItems != nil {
+		if repeatedRules.MinItems != nil && !repeatedRules.GetIgnoreEmpty() {
+			// an empty list is valid with ignore_empty, so there is no lower bound
 			schema.Schema.MinItems = int64(*repeatedRules.MinItems)
 		}
 		if repeatedRules.MaxItems != nil {
 			schema.Schema.MaxItems = int64(*repeatedRules.MaxItems)
 		}
+		if repeatedRules.GetUnique() {
+			schema.Schema.UniqueItems = true
+		}
 
 		// pull out the array items field rules
 		fieldRules := repeatedRules.Items
@@ -59,10 +78,12 @@ func (g *OpenAPIv3Generator) addValidationRules(fieldSchema *v3.SchemaOrReferenc
 			// no item specific rules
 			return
 		}
-		schema := schema.Schema.Items.SchemaOrReference[0]
-		fieldRule(fieldRules, field, schema.Oneof.(*v3.SchemaOrReference_Schema))
-
-		log.Printf("(TODO) Unsupported field type: list.")
+		itemSchema, ok := listItemsSchema(schema.Schema)
+		if !ok {
+			// message items are references, their rules belong to the referenced schema
+			return
+		}
+		fieldRule(fieldRules, field, itemSchema)
 		return
 	}
 
@@ -102,8 +123,8 @@ func fieldRule(fieldRules *validate.FieldRules, field protoreflect.FieldDescript
 		} else if stringRules.GetUuid() {
 			schema.Schema.Format = "uuid"
 		}
-		// Set min/max
-		if stringRules.GetMinLen() > 0 {
+		// Set min/max, an empty string is valid with ignore_empty
+		if stringRules.GetMinLen() > 0 && !stringRules.GetIgnoreEmpty() {
 			schema.Schema.MinLength = int64(stringRules.GetMinLen())
 		}
 		if stringRules.GetMaxLen() > 0 {
@@ -119,11 +140,17 @@ func fieldRule(fieldRules *validate.FieldRules, field protoreflect.FieldDescript
 		if int32Rules == nil {
 			break
 		}
-		if int32Rules.GetGte() > 0 {
-			schema.Schema.Minimum = float64(int32Rules.GetGte())
+		if int32Rules.Gt != nil {
+			setMinimum(schema.Schema, float64(int32Rules.GetGt()), true)
+		}
+		if int32Rules.Gte != nil {
+			setMinimum(schema.Schema, float64(int32Rules.GetGte()), false)
 		}
-		if int32Rules.GetLte() > 0 {
-			schema.Schema.Maximum = float64(int32Rules.GetLte())
+		if int32Rules.Lt != nil {
+			setMaximum(schema.Schema, float64(int32Rules.GetLt()), true)
+		}
+		if int32Rules.Lte != nil {
+			setMaximum(schema.Schema, float64(int32Rules.GetLte()), false)
 		}
 
 	case protoreflect.Int64Kind:
@@ -131,11 +158,17 @@ func fieldRule(fieldRules *validate.FieldRules, field protoreflect.FieldDescript
 		if int64Rules == nil {
 			break
 		}
-		if int64Rules.GetGte() > 0 {
-			schema.Schema.Minimum = float64(int64Rules.GetGte())
+		if int64Rules.Gt != nil {
+			setMinimum(schema.Schema, float64(int64Rules.GetGt()), true)
+		}
+		if int64Rules.Gte != nil {
+			setMinimum(schema.Schema, float64(int64Rules.GetGte()), false)
 		}
-		if int64Rules.GetLte() > 0 {
-			schema.Schema.Maximum = float64(int64Rules.GetLte())
+		if int64Rules.Lt != nil {
+			setMaximum(schema.Schema, float64(int64Rules.GetLt()), true)
+		}
+		if int64Rules.Lte != nil {
+			setMaximum(schema.Schema, float64(int64Rules.GetLte()), false)
 		}
 	case protoreflect.EnumKind:
 		enumRules := fieldRules.GetEnum()
@@ -145,7 +178,7 @@ func fieldRule(fieldRules *validate.FieldRules, field protoreflect.FieldDescript
 
 		var validEnums []int32
 		if enumRules.Const != nil {
-			validEnums = enumValues(field, true)
+			validEnums = []int32{enumRules.GetConst()}
 		} else if enumRules.In != nil {
 			validEnums = enumRules.In
 		} else if enumRules.NotIn != nil {
@@ -171,3 +204,234 @@ func fieldRule(fieldRules *validate.FieldRules, field protoreflect.FieldDescript
 		log.Printf("(TODO) Unsupported field type: %+v", fullMessageTypeName(field.Message()))
 	}
 }
+
+// listItemsSchema returns the inline schema for the items of a list schema. Lists of
+// messages reference their item schema, so there is no inline schema for them.
+func listItemsSchema(schema *v3.Schema) (*v3.SchemaOrReference_Schema, bool) {
+	if schema.Items == nil || len(schema.Items.SchemaOrReference) == 0 {
+		return nil, false
+	}
+	itemSchema, ok := schema.Items.SchemaOrReference[0].Oneof.(*v3.SchemaOrReference_Schema)
+	return itemSchema, ok
+}
+
+// addMessageValidationRules maps message and oneof level validation rules onto a message schema.
+func (g *OpenAPIv3Generator) addMessageValidationRules(schema *v3.Schema, message *protogen.Message) {
+	for _, oneof := range message.Oneofs {
+		if oneof.Desc.IsSynthetic() {
+			continue
+		}
+		if pgvOneofRequired(oneof.Desc) || protoValidateOneofRequired(oneof.Desc) {
+			names := []string{}
+			for _, field := range oneof.Fields {
+				names = append(names, g.reflect.formatFieldName(field.Desc))
+			}
+			addExactlyOneOf(schema, names)
+		}
+	}
+
+	messageRules := protoValidateMessageRules(message.Desc)
+	if messageRules == nil {
+		return
+	}
+	for _, rule := range messageRules.GetOneof() {
+		names := []string{}
+		for _, name := range rule.GetFields() {
+			if field := g.findField(name, message); field != nil {
+				names = append(names, g.reflect.formatFieldName(field.Desc))
+			}
+		}
+		if rule.GetRequired() {
+			addExactlyOneOf(schema, names)
+		} else {
+			addAtMostOneOf(schema, names)
+		}
+	}
+
+	cel := []*celRule{}
+	for _, rule := range messageRules.GetCel() {
+		cel = append(cel, &celRule{ID: rule.GetId(), Message: rule.GetMessage(), Expression: rule.GetExpression()})
+	}
+	for _, expression := range messageRules.GetCelExpression() {
+		cel = append(cel, &celRule{Expression: expression})
+	}
+	addCelRules(schema, cel)
+}
+
+// validationRequired reports whether the validation rules require a field to be set.
+func validationRequired(field protoreflect.FieldDescriptor) bool {
+	return pgvRequired(field) || protoValidateRequired(field)
+}
+
+func pgvRequired(field protoreflect.FieldDescriptor) bool {
+	if pgvDisabled(field.ContainingMessage()) {
+		return false
+	}
+	fieldRules, ok := proto.GetExtension(field.Options(), validate.E_Rules).(*validate.FieldRules)
+	return ok && fieldRules.GetMessage().GetRequired()
+}
+
+func pgvOneofRequired(oneof protoreflect.OneofDescriptor) bool {
+	if pgvDisabled(oneof.Parent().(protoreflect.MessageDescriptor)) {
+		return false
+	}
+	required, ok := proto.GetExtension(oneof.Options(), validate.E_Required).(bool)
+	return ok && required
+}
+
+// pgvDisabled reports whether protoc-gen-validate rules are turned off for a message.
+func pgvDisabled(message protoreflect.MessageDescriptor) bool {
+	if message == nil {
+		return false
+	}
+	disabled, _ := proto.GetExtension(message.Options(), validate.E_Disabled).(bool)
+	ignored, _ := proto.GetExtension(message.Options(), validate.E_Ignored).(bool)
+	return disabled || ignored
+}
+
+// addExactlyOneOf requires exactly one of the named properties to be set.
+func addExactlyOneOf(schema *v3.Schema, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	oneOf := []*v3.SchemaOrReference{}
+	for _, name := range names {
+		oneOf = append(oneOf, &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{Required: []string{name}}}})
+	}
+	if len(schema.OneOf) == 0 {
+		schema.OneOf = oneOf
+		return
+	}
+	schema.AllOf = append(schema.AllOf, &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Schema{
+			Schema: &v3.Schema{OneOf: oneOf}}})
+}
+
+// addAtMostOneOf forbids any two of the named properties to be set together.
+func addAtMostOneOf(schema *v3.Schema, names []string) {
+	if len(names) < 2 {
+		return
+	}
+	pairs := []*v3.SchemaOrReference{}
+	for i := 0; i < len(names); i++ {
+		for j := i + 1; j < len(names); j++ {
+			pairs = append(pairs, &v3.SchemaOrReference{
+				Oneof: &v3.SchemaOrReference_Schema{
+					Schema: &v3.Schema{Required: []string{names[i], names[j]}}}})
+		}
+	}
+	not := &v3.Schema{AnyOf: pairs}
+	if schema.Not == nil {
+		schema.Not = not
+		return
+	}
+	schema.AllOf = append(schema.AllOf, &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Schema{
+			Schema: &v3.Schema{Not: not}}})
+}
+
+// The OpenAPI model drops zero values, so zero bounds are kept in placeholder extensions
+// until the document is rendered, see expandZeroBounds.
+const (
+	zeroMinimumExtension = "x-zero-minimum"
+	zeroMaximumExtension = "x-zero-maximum"
+)
+
+// setMinimum sets the lower bound of a schema.
+func setMinimum(schema *v3.Schema, minimum float64, exclusive bool) {
+	schema.Minimum = minimum
+	schema.ExclusiveMinimum = exclusive
+	removeSchemaExtension(schema, zeroMinimumExtension)
+	if minimum == 0 {
+		addSchemaExtension(schema, zeroMinimumExtension, 0)
+	}
+}
+
+// setMaximum sets the upper bound of a schema.
+func setMaximum(schema *v3.Schema, maximum float64, exclusive bool) {
+	schema.Maximum = maximum
+	schema.ExclusiveMaximum = exclusive
+	removeSchemaExtension(schema, zeroMaximumExtension)
+	if maximum == 0 {
+		addSchemaExtension(schema, zeroMaximumExtension, 0)
+	}
+}
+
+// boundKeywords are the keywords of a rendered schema that follow the bounds, in order.
+var boundKeywords = []string{"maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "maxProperties", "minProperties", "required", "enum", "type"}
+
+// expandZeroBounds replaces the placeholder extensions of zero bounds in a rendered document
+// with minimum and maximum keywords, in the place the model would render them.
+func expandZeroBounds(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			expandZeroBounds(node.Content[i+1])
+		}
+		for _, bound := range []struct{ extension, keyword string }{
+			{zeroMaximumExtension, "maximum"},
+			{zeroMinimumExtension, "minimum"},
+		} {
+			i := mappingIndex(node, bound.extension)
+			if i < 0 {
+				continue
+			}
+			value := node.Content[i+1]
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			at := len(node.Content)
+			for _, keyword := range boundKeywords[indexOf(boundKeywords, bound.keyword)+1:] {
+				if j := mappingIndex(node, keyword); j >= 0 {
+					at = j
+					break
+				}
+			}
+			node.Content = insertMappingPair(node.Content, at, bound.keyword, value)
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, item := range node.Content {
+			expandZeroBounds(item)
+		}
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// addCelRules lists CEL rules that can't be expressed in OpenAPI in the x-cel extension.
+func addCelRules(schema *v3.Schema, rules []*celRule) {
+	if len(rules) == 0 {
+		return
+	}
+	for _, ext := range schema.SpecificationExtension {
+		if ext.Name == "x-cel" {
+			var existing []*celRule
+			if err := yaml.Unmarshal([]byte(ext.Value.Yaml), &existing); err == nil {
+				rules = append(existing, rules...)
+			}
+			ext.Value = newV3Any(rules)
+			return
+		}
+	}
+	schema.SpecificationExtension = append(schema.SpecificationExtension, &v3.NamedAny{
+		Name:  "x-cel",
+		Value: newV3Any(rules),
+	})
+}
+
+// newV3Any marshals a value to be used as an OpenAPI value.
+func newV3Any(value interface{}) *v3.Any {
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		log.Printf("unable to marshal value %v: %s", value, err)
+		return &v3.Any{}
+	}
+	return &v3.Any{Yaml: string(bytes)}
+}
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// inputSchemaSuffix is appended to the schema name of the input variant of a message.
+	inputSchemaSuffix = "Input"
+	// updateSchemaSuffix is appended to the schema name of the partial variant of a message,
+	// used by the body of update methods.
+	updateSchemaSuffix = "Update"
+)
+
+// schemaVariant selects the fields of a message that are part of its schema.
+type schemaVariant int
+
+const (
+	schemaVariantAll    schemaVariant = iota // All fields, one schema for requests and responses.
+	schemaVariantOutput                      // Fields in responses, without write-only fields.
+	schemaVariantInput                       // Fields in requests, without read-only fields.
+	schemaVariantUpdate                      // Fields in partial updates, without read-only fields and only the identifier required.
+)
+
+// includes reports whether the field is part of the variant.
+func (v schemaVariant) includes(field protoreflect.FieldDescriptor) bool {
+	switch v {
+	case schemaVariantOutput:
+		return !fieldWriteOnly(field)
+	case schemaVariantInput:
+		return !fieldReadOnly(field)
+	case schemaVariantUpdate:
+		// The identifier names the resource to update.
+		return !fieldReadOnly(field) || hasFieldBehavior(field, fieldBehaviorIdentifier)
+	}
+	return true
+}
+
+// fieldReadOnly reports whether a field is ignored in requests.
+func fieldReadOnly(field protoreflect.FieldDescriptor) bool {
+	return hasFieldBehavior(field, annotations.FieldBehavior_OUTPUT_ONLY) ||
+		hasFieldBehavior(field, fieldBehaviorIdentifier)
+}
+
+// fieldWriteOnly reports whether a field is left out of responses.
+func fieldWriteOnly(field protoreflect.FieldDescriptor) bool {
+	return hasFieldBehavior(field, annotations.FieldBehavior_INPUT_ONLY)
+}
+
+// outputVariant returns the variant used for the schema of a message referenced by responses.
+func (r *OpenAPIv3Reflector) outputVariant(message protoreflect.MessageDescriptor) schemaVariant {
+	if r.hasVariants(message) {
+		return schemaVariantOutput
+	}
+	return schemaVariantAll
+}
+
+// hasVariants reports whether a message has separate input and output schemas, which is the
+// case with input_schemas for messages with read-only or write-only fields, directly or in
+// any of the messages they use.
+func (r *OpenAPIv3Reflector) hasVariants(message protoreflect.MessageDescriptor) bool {
+	if r.conf.InputSchemas == nil || !*r.conf.InputSchemas {
+		return false
+	}
+	return messageHasVariants(message, map[protoreflect.FullName]bool{})
+}
+
+func messageHasVariants(message protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) bool {
+	if seen[message.FullName()] {
+		return false
+	}
+	seen[message.FullName()] = true
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if fieldReadOnly(field) || fieldWriteOnly(field) {
+			return true
+		}
+		if field.IsMap() {
+			field = field.MapValue()
+		}
+		if field.Message() != nil && messageHasVariants(field.Message(), seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVariantSchemaName reports whether the schema name of a variant of a message is free.
+// Otherwise a message has the same schema name, e.g. BookInput for the input variant of Book,
+// and the generation fails, as one of the schemas would replace the other.
+func (g *OpenAPIv3Generator) checkVariantSchemaName(message *protogen.Message, schemaName string) bool {
+	if g.messageSchemas == nil {
+		g.messageSchemas = map[string]protoreflect.FullName{}
+		var addMessages func(messages []*protogen.Message)
+		addMessages = func(messages []*protogen.Message) {
+			for _, m := range messages {
+				g.messageSchemas[g.reflect.formatMessageName(m.Desc)] = m.Desc.FullName()
+				addMessages(m.Messages)
+			}
+		}
+		for _, file := range g.plugin.Files {
+			addMessages(file.Messages)
+		}
+	}
+	other, ok := g.messageSchemas[schemaName]
+	if !ok {
+		return true
+	}
+	if g.err == nil {
+		g.err = fmt.Errorf("the schema %s of a variant of %s has the same name as the schema of %s, rename the message", schemaName, message.Desc.FullName(), other)
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"strings"
+
+	v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/genproto/googleapis/api/visibility"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	open_api_extensions "github.com/kollalabs/protoc-gen-openapi/openapi"
+)
+
+// visibilityTags returns the build tags of an element: the build tags of its openapi visibility,
+// and the labels of its google.api visibility rule.
+func visibilityTags(options proto.Message, extension, rule protoreflect.ExtensionType) []string {
+	tags := []string{}
+	if v, ok := proto.GetExtension(options, extension).(*open_api_extensions.Visibility); ok {
+		tags = append(tags, v.GetBuildTags()...)
+	}
+	return append(tags, visibilityLabels(options, rule)...)
+}
+
+// visibilityLabels returns the labels of the restriction of a google.api visibility rule.
+func visibilityLabels(options proto.Message, rule protoreflect.ExtensionType) []string {
+	labels := []string{}
+	if r, ok := proto.GetExtension(options, rule).(*visibility.VisibilityRule); ok {
+		for _, label := range strings.Split(r.GetRestriction(), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// methodBuildTags returns the build tags of a method: the build tags of its openapi.method_params
+// and the labels of its google.api.method_visibility, or else the build tags of its service
+// (openapi.service_visibility and google.api.api_visibility), or else of its file.
+func methodBuildTags(method *protogen.Method, methodParams *open_api_extensions.Parameters) []string {
+	tags := append(append([]string{}, methodParams.GetBuildTags()...), visibilityLabels(method.Desc.Options(), visibility.E_MethodVisibility)...)
+	if len(tags) == 0 {
+		tags = visibilityTags(method.Parent.Desc.Options(), open_api_extensions.E_ServiceVisibility, visibility.E_ApiVisibility)
+	}
+	if len(tags) == 0 {
+		if v, ok := proto.GetExtension(method.Desc.ParentFile().Options(), open_api_extensions.E_FileVisibility).(*open_api_extensions.Visibility); ok {
+			tags = v.GetBuildTags()
+		}
+	}
+	return tags
+}
+
+// methodIncluded reports whether a method is documented: its build tags match the build tag
+// expressions. Methods without build tags are left out if the untagged option excludes them.
+func (g *OpenAPIv3Generator) methodIncluded(method *protogen.Method, methodParams *open_api_extensions.Parameters) bool {
+	tags := methodBuildTags(method, methodParams)
+	if len(tags) == 0 && g.reflect.excludesUntagged(*g.conf.Untagged) {
+		return false
+	}
+	return g.reflect.includesBuildTags(tags, true)
+}
+
+// fieldVisible reports whether a field is documented: its build tags, and the build tags of its
+// message (or of the message of its map values), match the build tag expression.
+func (r *OpenAPIv3Reflector) fieldVisible(field protoreflect.FieldDescriptor) bool {
+	if !r.includesBuildTags(visibilityTags(field.Options(), open_api_extensions.E_FieldVisibility, visibility.E_FieldVisibility), true) {
+		return false
+	}
+	message := field.Message()
+	if field.IsMap() {
+		message = field.MapValue().Message()
+	}
+	return message == nil || r.messageVisible(message)
+}
+
+// messageVisible reports whether the fields typed with a message are documented.
+func (r *OpenAPIv3Reflector) messageVisible(message protoreflect.MessageDescriptor) bool {
+	return r.includesBuildTags(visibilityTags(message.Options(), open_api_extensions.E_MessageVisibility, visibility.E_MessageVisibility), true)
+}
+
+// enumValueVisible reports whether an enum value is documented.
+func (r *OpenAPIv3Reflector) enumValueVisible(value protoreflect.EnumValueDescriptor) bool {
+	return r.includesBuildTags(visibilityTags(value.Options(), open_api_extensions.E_EnumValueVisibility, visibility.E_ValueVisibility), true)
+}
+
+// removeHiddenEnumValues removes the enum values that aren't documented from the schema of an
+// enum field, or of its items.
+func (r *OpenAPIv3Reflector) removeHiddenEnumValues(fieldSchema *v3.SchemaOrReference, field protoreflect.FieldDescriptor) {
+	if field.Enum() == nil || fieldSchema == nil {
+		return
+	}
+	hidden := []string{}
+	values := field.Enum().Values()
+	for i := 0; i < values.Len(); i++ {
+		if !r.enumValueVisible(values.Get(i)) {
+			hidden = append(hidden, string(values.Get(i).Name()))
+		}
+	}
+	if len(hidden) == 0 {
+		return
+	}
+	schema := fieldSchema.GetSchema()
+	if items := schema.GetItems().GetSchemaOrReference(); field.IsList() && len(items) > 0 {
+		schema = items[0].GetSchema()
+	}
+	if schema == nil {
+		return
+	}
+	enum := []*v3.Any{}
+	for _, value := range schema.Enum {
+		if !contains(hidden, value.Yaml) {
+			enum = append(enum, value)
+		}
+	}
+	schema.Enum = enum
+}
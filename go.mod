@@ -3,13 +3,11 @@ module github.com/kollalabs/protoc-gen-openapi
 go 1.23
 
 require (
+	buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.33.0-20260825204119-511051f7f437.1
 	github.com/envoyproxy/protoc-gen-validate v0.9.1
+	github.com/golang/protobuf v1.5.2
 	github.com/google/gnostic v0.6.9
 	google.golang.org/genproto v0.0.0-20220107163113-42d7afdf6368
 	google.golang.org/protobuf v1.33.0
-)
-
-require (
-	github.com/golang/protobuf v1.5.2 // indirect
-	gopkg.in/yaml.v3 v3.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0
 )
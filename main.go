@@ -25,17 +25,37 @@ import (
 var flags flag.FlagSet
 
 func main() {
+	buildTags := generator.BuildTags{}
+	flags.Var(&buildTags, "build_tag", `build tag expression selecting the methods and headers to document by their build tags, e.g. "public_docs && !beta". Can be repeated, and elements matching any of the expressions are documented`)
+
 	conf := generator.Configuration{
-		Version:         flags.String("version", "0.0.1", "version number text, e.g. 1.2.3"),
-		Title:           flags.String("title", "", "name of the API"),
-		Description:     flags.String("description", "", "description of the API"),
-		Naming:          flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
-		FQSchemaNaming:  flags.Bool("fq_schema_naming", false, `schema naming convention. If "true", generates fully-qualified schema names by prefixing them with the proto message package name`),
-		EnumType:        flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
-		CircularDepth:   flags.Int("depth", 2, "depth of recursion for circular messages"),
-		DefaultResponse: flags.Bool("default_response", true, `add default response. If "true", automatically adds a default response to operations which use the google.rpc.Status message. Useful if you use envoy or grpc-gateway to transcode as they use this type for their default error responses.`),
-		Validate:        flags.Bool("validate", false, "parse protoc-gen-validate options that are supported into openapi field options"),
-		BuildTag:        flags.String("build_tag", "", "build tag to add to the generated files"),
+		Version:                flags.String("version", "0.0.1", "version number text, e.g. 1.2.3"),
+		Title:                  flags.String("title", "", "name of the API"),
+		Description:            flags.String("description", "", "description of the API"),
+		Naming:                 flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
+		FQSchemaNaming:         flags.Bool("fq_schema_naming", false, `schema naming convention. If "true", generates fully-qualified schema names by prefixing them with the proto message package name`),
+		EnumType:               flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
+		CircularDepth:          flags.Int("depth", 2, "depth of recursion for circular messages"),
+		DefaultResponse:        flags.Bool("default_response", true, `add default response. If "true", automatically adds a default response to operations which use the google.rpc.Status message. Useful if you use envoy or grpc-gateway to transcode as they use this type for their default error responses.`),
+		Validate:               flags.Bool("validate", false, "parse protoc-gen-validate options that are supported into openapi field options"),
+		BuildTags:              &buildTags,
+		Untagged:               flags.String("untagged", generator.DefaultUntagged, `methods without build tags when there is a build_tag expression. Use "exclude" to only document the methods whose build tags match, "include" to document them too, or build tags separated by semicolons to leave them out when the expression holds for one of the tags`),
+		ResourceIDPattern:      flags.String("resource_id_pattern", generator.DefaultResourceIDPattern, "pattern for the variables of google.api.resource name patterns"),
+		InputSchemas:           flags.Bool("input_schemas", false, `separate request and response schemas. If "true", messages with read-only or write-only fields get an input variant (e.g. "BookInput") without the read-only fields for request bodies, and the write-only fields are left out of the response schema`),
+		PaginationDescriptions: flags.Bool("pagination_descriptions", false, `add the standard AIP-158 descriptions to page size and page token parameters without a description`),
+		SuccessStatusCodes:     flags.Bool("success_status_codes", false, `use the AIP status codes for success responses. If "true", Create methods respond with 201, and methods returning google.protobuf.Empty with 204 and no content`),
+		ErrorModel:             flags.String("error_model", generator.ErrorModelStatus, `schema of error responses. Use "problem" for RFC 7807 problem details (application/problem+json) instead of google.rpc.Status`),
+		StreamingContent:       flags.String("streaming_content", generator.StreamingContentGrpcGateway, `content of server streaming responses. Use "grpc_gateway" for newline-delimited {"result": ...} objects, "ndjson" for application/x-ndjson or "sse" for text/event-stream`),
+		ClientStreaming:        flags.String("client_streaming", generator.ClientStreamingDocument, `client and bidi streaming methods. Use "document" to document them with a limitation note, or "exclude" to leave them out`),
+		AsyncAPI:               flags.Bool("asyncapi", false, `generate an AsyncAPI document (asyncapi.yaml) for streaming methods and messages annotated with openapi.event. Operations have the operation ID of their method (<Service>_<Method>), and the publish operations of bidi streaming methods a _Send suffix`),
+		OpenAPIVersion:         flags.String("openapi_version", generator.OpenAPIVersion30, `version of the OpenAPI document. Use "3.1" for OpenAPI 3.1, whose schemas are JSON Schema 2020-12, or "2.0" for Swagger 2.0`),
+		OutputFormat:           flags.String("output_format", generator.OutputFormatYAML, `format of the generated files: "yaml", "json" or "both"`),
+		OutputFile:             flags.String("output_file", generator.DefaultOutputFile, `path of the generated file, relative to the output directory. JSON files get a .json extension`),
+		OutputMode:             flags.String("output_mode", generator.OutputModeSingle, `documents to generate: "single", or one per service, file or package with "per_service", "per_file" or "per_package". Split documents are named after their unit, in the directory of output_file`),
+		SharedPackages:         flags.String("shared_packages", "", `packages whose schemas are generated in shared documents, separated by semicolons (e.g. "google.rpc;common.v1"). Each package gets a components document named after it, in the directory of output_file, which the other documents reference`),
+		BundleFile:             flags.String("bundle_file", "", `path of a bundled document, relative to the output directory. The generated documents and the shared documents are flattened into it, with local references only`),
+		Variants:               flags.String("variants", "", `documents to generate with their own build tags, separated by semicolons (e.g. "public:public_docs;internal:"). Each variant is a name and a build tag expression, and is generated in a directory named after it. The build_tag option is ignored`),
+		IncludeMessages:        flags.String("include_messages", "", `patterns of the full names of messages whose schemas are always generated, separated by semicolons (e.g. "tests.events.v1.*Event"). A "*" matches any characters`),
 	}
 
 	opts := protogen.Options{
@@ -28,20 +28,50 @@ var openapiTests = []struct {
 	path      string
 	protofile string
 	buildTag  []string
+	options   []string
+	output    string   // Generated file, defaults to "openapi.yaml".
+	outputs   []string // Other generated files to compare, e.g. "asyncapi.yaml".
 }{
 	{name: "Google Library example", path: "examples/google/example/library/v1/", protofile: "library.proto"},
 	{name: "Body mapping", path: "examples/tests/bodymapping/", protofile: "message.proto"},
 	{name: "Map fields", path: "examples/tests/mapfields/", protofile: "message.proto"},
 	{name: "Path params", path: "examples/tests/pathparams/", protofile: "message.proto"},
+	{name: "Path param validation", path: "examples/tests/pathvalidation/", protofile: "message.proto"},
 	{name: "Protobuf types", path: "examples/tests/protobuftypes/", protofile: "message.proto"},
 	{name: "JSON options", path: "examples/tests/jsonoptions/", protofile: "message.proto"},
 	{name: "Ignore services without annotations", path: "examples/tests/noannotations/", protofile: "message.proto"},
 	{name: "Handle enums", path: "examples/tests/enums/", protofile: "message.proto"},
 	{name: "Better summary", path: "examples/tests/summary/", protofile: "message.proto"},
 	{name: "Message name pattern", path: "examples/tests/messagenamepattern/", protofile: "message.proto"},
+	{name: "Resource references", path: "examples/tests/resourcereferences/", protofile: "message.proto"},
+	{name: "Resource ID patterns", path: "examples/tests/resourceidpatterns/", protofile: "message.proto", options: []string{"resource_id_pattern=[0-9]+"}},
 	{name: "Validate", path: "examples/tests/validate/", protofile: "message.proto"},
+	{name: "Protovalidate", path: "examples/tests/protovalidate/", protofile: "message.proto"},
 	{name: "Field behaviors", path: "examples/tests/fieldbehaviors/", protofile: "message.proto"},
+	{name: "Pagination", path: "examples/tests/pagination/", protofile: "message.proto", options: []string{"pagination_descriptions=true"}},
+	{name: "Long-running operations", path: "examples/tests/longrunning/", protofile: "message.proto"},
+	{name: "Error responses", path: "examples/tests/errors/", protofile: "message.proto"},
+	{name: "Success responses", path: "examples/tests/successresponses/", protofile: "message.proto", options: []string{"success_status_codes=true"}},
+	{name: "Problem details", path: "examples/tests/problemdetails/", protofile: "message.proto", options: []string{"error_model=problem"}},
+	{name: "Streaming", path: "examples/tests/streaming/", protofile: "message.proto"},
+	{name: "Streaming (SSE)", path: "examples/tests/streamingsse/", protofile: "message.proto", options: []string{"streaming_content=sse", "client_streaming=exclude"}},
+	{name: "Streaming with problem details", path: "examples/tests/streamingproblem/", protofile: "message.proto", options: []string{"error_model=problem"}},
+	{name: "AsyncAPI", path: "examples/tests/asyncapi/", protofile: "message.proto", options: []string{"asyncapi=true"}, outputs: []string{"asyncapi.yaml"}},
+	{name: "AsyncAPI visibility", path: "examples/tests/asyncapivisibility/", protofile: "message.proto", buildTag: []string{"public_docs"}, options: []string{"asyncapi=true", "output_mode=per_service", "client_streaming=exclude"}, output: "tests.asyncapivisibility.message.v1.Messaging.yaml", outputs: []string{"tests.asyncapivisibility.message.v1.Messaging.asyncapi.yaml", "tests.asyncapivisibility.message.v1.Moderation.yaml", "tests.asyncapivisibility.message.v1.Moderation.asyncapi.yaml"}},
+	{name: "OpenAPI 3.1", path: "examples/tests/openapi31/", protofile: "message.proto"},
+	{name: "Swagger 2.0", path: "examples/tests/swagger/", protofile: "message.proto", options: []string{"openapi_version=2.0"}},
+	{name: "Output file", path: "examples/tests/outputfile/", protofile: "message.proto", options: []string{"output_format=both", "output_file=docs/messaging.yaml"}, output: "docs/messaging.yaml", outputs: []string{"docs/messaging.json"}},
+	{name: "Output mode per service", path: "examples/tests/outputmode/", protofile: "message.proto", options: []string{"output_mode=per_service"}, output: "tests.outputmode.message.v1.Messaging.yaml", outputs: []string{"tests.outputmode.message.v1.Channels.yaml"}},
+	{name: "Shared schemas", path: "examples/tests/sharedschemas/", protofile: "message.proto", options: []string{"output_mode=per_service", "shared_packages=google.rpc;tests.sharedschemas.common.v1", "bundle_file=bundle.yaml"}, output: "tests.sharedschemas.message.v1.Orders.yaml", outputs: []string{"tests.sharedschemas.message.v1.Invoices.yaml", "google.rpc.yaml", "tests.sharedschemas.common.v1.yaml", "bundle.yaml"}},
+	{name: "Build tag expressions", path: "examples/tests/buildtagexpressions/", protofile: "message.proto", options: []string{"build_tag=public_docs && !beta"}},
+	{name: "Visibility", path: "examples/tests/visibility/", protofile: "message.proto", options: []string{"build_tag=public_docs", "untagged=include"}},
+	{name: "Service visibility", path: "examples/tests/servicevisibility/", protofile: "message.proto", options: []string{"build_tag=public_docs"}},
+	{name: "Include messages", path: "examples/tests/includemessages/", protofile: "message.proto", buildTag: []string{"public_docs"}, options: []string{"include_messages=tests.includemessages.message.v1.*Event"}},
+	{name: "Variants", path: "examples/tests/variants/", protofile: "message.proto", options: []string{"variants=public:public_docs;internal:internal || public_docs", "output_file=variants.yaml"}, output: "public/variants.yaml", outputs: []string{"internal/variants.yaml"}},
+	{name: "Update mask", path: "examples/tests/updatemask/", protofile: "message.proto"},
+	{name: "Input schemas", path: "examples/tests/inputschemas/", protofile: "message.proto", options: []string{"input_schemas=true"}},
 	{name: "Custom Params", path: "examples/tests/customparams/", protofile: "message.proto"},
+	{name: "Custom Params example", path: "examples/tests/customparamsexample/", protofile: "message.proto"},
 	{name: "Custom Params with build tag set", path: "examples/tests/customparamsbuildtag/", protofile: "message.proto", buildTag: []string{"postman"}},
 	{name: "Custom Params with build tag set for excluding method", path: "examples/tests/customparamsexclude/", protofile: "message.proto", buildTag: []string{"public_docs"}},
 	{name: "Custom Params with build tag postman", path: "examples/tests/customparamspostmanonly/", protofile: "message.proto", buildTag: []string{"postman"}},
@@ -59,6 +89,9 @@ func TestOpenAPIProtobufNaming(t *testing.T) {
 					openAPICommand += ",build_tag=" + tag
 				}
 			}
+			for _, option := range tt.options {
+				openAPICommand += "," + option
+			}
 			openAPICommand += ":."
 			cmd := []string{
 				"-I",
@@ -76,7 +109,7 @@ func TestOpenAPIProtobufNaming(t *testing.T) {
 				t.Fatalf("protoc failed: %+v", err)
 			}
 			// Verify that the generated spec matches our expected version.
-			diffArgs := []string{"-u", "--color", path.Join(tt.path, "openapi.yaml"), "openapi.yaml"}
+			diffArgs := []string{"-u", "--color", path.Join(tt.path, "openapi.yaml"), outputFile(tt.output)}
 			output, err := exec.Command("diff", diffArgs...).CombinedOutput()
 			if err != nil {
 				fmt.Printf("Protoc output:\n%s\n", out)
@@ -86,7 +119,9 @@ func TestOpenAPIProtobufNaming(t *testing.T) {
 				t.Fatalf("Diff failed: %+v", err)
 			}
 			// if the test succeeded, clean up
-			os.Remove("openapi.yaml")
+			os.Remove(outputFile(tt.output))
+			diffOutputs(t, tt.path, tt.outputs, "")
+			removeOutputDirs([]string{outputFile(tt.output)})
 		})
 	}
 }
@@ -95,18 +130,100 @@ func TestOpenAPIJSONNaming(t *testing.T) {
 	for _, tt := range openapiTests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI spec with JSON naming.
+			openAPICommand := "--openapi_out=version=1.2.3,validate=true"
+			for _, option := range tt.options {
+				openAPICommand += "," + option
+			}
+			openAPICommand += ":."
+			out, err := exec.Command("protoc",
+				"-I", "./",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				openAPICommand).CombinedOutput()
+			if err != nil {
+				fmt.Println(string(out))
+				t.Fatalf("protoc failed: %+v", err)
+			}
+
+			// Verify that the generated spec matches our expected version.
+			diffArgs := []string{"-u", "--color", path.Join(tt.path, "openapi_json.yaml"), outputFile(tt.output)}
+			output, err := exec.Command("diff", diffArgs...).CombinedOutput()
+			if err != nil {
+				fmt.Printf("Protoc output:\n%s\n", out)
+				fmt.Println("Command: diff", strings.Join(diffArgs, " "))
+				fmt.Println(string(output))
+				t.Fatalf("Diff failed: %+v", err)
+			}
+			// if the test succeeded, clean up
+			os.Remove(outputFile(tt.output))
+			diffOutputs(t, tt.path, tt.outputs, "_json")
+			removeOutputDirs([]string{outputFile(tt.output)})
+		})
+	}
+}
+
+// outputFile returns the generated file of a test.
+func outputFile(output string) string {
+	if output == "" {
+		return "openapi.yaml"
+	}
+	return output
+}
+
+// diffOutputs verifies that other generated files match our expected versions, which have
+// the suffix before their extension.
+func diffOutputs(t *testing.T, dir string, outputs []string, suffix string) {
+	for _, output := range outputs {
+		ext := path.Ext(output)
+		expected := path.Join(dir, strings.TrimSuffix(path.Base(output), ext)+suffix+ext)
+		diffArgs := []string{"-u", "--color", expected, output}
+		out, err := exec.Command("diff", diffArgs...).CombinedOutput()
+		if err != nil {
+			fmt.Println("Command: diff", strings.Join(diffArgs, " "))
+			fmt.Println(string(out))
+			t.Fatalf("Diff failed: %+v", err)
+		}
+		os.Remove(output)
+	}
+	removeOutputDirs(outputs)
+}
+
+// removeOutputDirs removes the empty directories of generated files.
+func removeOutputDirs(outputs []string) {
+	for _, output := range outputs {
+		if dir := path.Dir(output); dir != "." {
+			os.Remove(dir)
+		}
+	}
+}
+
+func TestOpenAPI31(t *testing.T) {
+	for _, tt := range openapiTests {
+		if !strings.HasPrefix(tt.path, "examples/tests/") {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI 3.1 spec.
+			openAPICommand := "--openapi_out=version=0.0.1,naming=proto,validate=true"
+			for _, tag := range tt.buildTag {
+				openAPICommand += ",build_tag=" + tag
+			}
+			for _, option := range tt.options {
+				openAPICommand += "," + option
+			}
+			openAPICommand += ",openapi_version=3.1:."
 			out, err := exec.Command("protoc",
 				"-I", "./",
 				"-I", "examples",
 				path.Join(tt.path, tt.protofile),
-				"--openapi_out=version=1.2.3,validate=true:.").CombinedOutput()
+				openAPICommand).CombinedOutput()
 			if err != nil {
 				fmt.Println(string(out))
 				t.Fatalf("protoc failed: %+v", err)
 			}
 
 			// Verify that the generated spec matches our expected version.
-			diffArgs := []string{"-u", "--color", path.Join(tt.path, "openapi_json.yaml"), "openapi.yaml"}
+			diffArgs := []string{"-u", "--color", path.Join(tt.path, "openapi_31.yaml"), outputFile(tt.output)}
 			output, err := exec.Command("diff", diffArgs...).CombinedOutput()
 			if err != nil {
 				fmt.Printf("Protoc output:\n%s\n", out)
@@ -115,7 +232,58 @@ func TestOpenAPIJSONNaming(t *testing.T) {
 				t.Fatalf("Diff failed: %+v", err)
 			}
 			// if the test succeeded, clean up
-			os.Remove("openapi.yaml")
+			os.Remove(outputFile(tt.output))
+			for _, output := range tt.outputs {
+				os.Remove(output)
+			}
+			removeOutputDirs(append(tt.outputs, outputFile(tt.output)))
+		})
+	}
+}
+
+var openapiErrorTests = []struct {
+	name      string
+	path      string
+	protofile string
+	options   []string
+	err       string
+}{
+	{name: "Error model", path: "examples/tests/errors/", protofile: "message.proto", options: []string{"error_model=bogus"}, err: `invalid error_model "bogus": must be status or problem`},
+	{name: "Streaming content", path: "examples/tests/streaming/", protofile: "message.proto", options: []string{"streaming_content=bogus"}, err: `invalid streaming_content "bogus": must be grpc_gateway, ndjson or sse`},
+	{name: "Client streaming", path: "examples/tests/streaming/", protofile: "message.proto", options: []string{"client_streaming=bogus"}, err: `invalid client_streaming "bogus": must be document or exclude`},
+	{name: "Build tag missing operand", path: "examples/tests/buildtagexpressions/", protofile: "message.proto", options: []string{"build_tag=a &&"}, err: `invalid build tag expression "a &&": missing build tag`},
+	{name: "Build tag missing parenthesis", path: "examples/tests/buildtagexpressions/", protofile: "message.proto", options: []string{"build_tag=(a"}, err: `invalid build tag expression "(a": missing )`},
+	{name: "Build tag extra parenthesis", path: "examples/tests/buildtagexpressions/", protofile: "message.proto", options: []string{"build_tag=a)"}, err: `invalid build tag expression "a)": unexpected )`},
+	{name: "Build tag missing operator", path: "examples/tests/buildtagexpressions/", protofile: "message.proto", options: []string{"build_tag=a b"}, err: `invalid build tag expression "a b": unexpected b`},
+	{name: "Build tag unknown operator", path: "examples/tests/buildtagexpressions/", protofile: "message.proto", options: []string{"build_tag=a | b"}, err: `invalid build tag expression "a | b": unexpected |`},
+	{name: "Untagged", path: "examples/tests/customparamsexclude/", protofile: "message.proto", options: []string{"untagged=a && b"}, err: `invalid untagged "a && b": must be include, exclude or build tags separated by semicolons`},
+	{name: "Shared package document", path: "examples/google/example/library/v1/", protofile: "library.proto", options: []string{"output_mode=per_package", "shared_packages=google.example.library.v1"}, err: "several documents are written to google.example.library.v1.yaml"},
+	{name: "Include messages pattern", path: "examples/tests/includemessages/", protofile: "message.proto", options: []string{"include_messages=tests.[a"}, err: `invalid include_messages pattern "tests.[a": syntax error in pattern`},
+	{name: "OpenAPI version", path: "examples/tests/openapi31/", protofile: "message.proto", options: []string{"openapi_version=3.1.0"}, err: `invalid openapi_version "3.1.0": must be 2.0, 3.0 or 3.1`},
+	{name: "Variant schema names", path: "examples/tests/variantnames/", protofile: "message.proto", options: []string{"input_schemas=true"}, err: "the schema BookInput of a variant of tests.variantnames.message.v1.Book has the same name as the schema of tests.variantnames.message.v1.BookInput"},
+}
+
+func TestOpenAPIErrors(t *testing.T) {
+	for _, tt := range openapiErrorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Run protoc and the protoc-gen-openapi plugin, which should fail with the error.
+			openAPICommand := "--openapi_out=version=0.0.1,naming=proto,validate=true"
+			for _, option := range tt.options {
+				openAPICommand += "," + option
+			}
+			openAPICommand += ":."
+			out, err := exec.Command("protoc",
+				"-I", "./",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				openAPICommand).CombinedOutput()
+			if err == nil {
+				os.Remove("openapi.yaml")
+				t.Fatalf("protoc succeeded, expected the error %q", tt.err)
+			}
+			if !strings.Contains(string(out), tt.err) {
+				t.Fatalf("protoc failed with %q, expected the error %q", out, tt.err)
+			}
 		})
 	}
 }